@@ -4,19 +4,75 @@
 
 package bearertoken
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Redacted is the value printed in place of a token or secret.
+const Redacted = "[REDACTED]"
+
 // Token represents a bearer token, generally sent by a REST client in a
 // Authorization or Cookie header for authentication purposes.
+//
+// Token redacts its value when it is printed using the fmt package or
+// marshalled as text, JSON or YAML. Use Reveal to access the value of the
+// token. Unmarshalling text, JSON or YAML into a Token stores the unmarshalled
+// value, so a Token can be read from configuration. Unlike Secret, the value of
+// a Token is not marshalled when the UnsafeRevealSecrets option of safejson or
+// safeyaml is provided; use Secret[string] if the value must be revealed.
 type Token string
 
-func (t Token) String() string {
+// Reveal returns the value of the token.
+func (t Token) Reveal() string {
 	return string(t)
 }
 
+// String returns Redacted. Use Reveal to access the value of the token.
+func (t Token) String() string {
+	return Redacted
+}
+
+// GoString returns a redacted Go representation of the token.
+func (t Token) GoString() string {
+	return `bearertoken.Token("` + Redacted + `")`
+}
+
+// Format implements fmt.Formatter so that the token is redacted for all verbs.
+func (t Token) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, t.GoString())
+}
+
+// MarshalText returns Redacted. Use Reveal to access the value of the token.
 func (t Token) MarshalText() ([]byte, error) {
-	return []byte(t), nil
+	return []byte(Redacted), nil
+}
+
+// MarshalJSON returns Redacted as a JSON string.
+func (t Token) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
 }
 
+// MarshalYAML returns Redacted.
+func (t Token) MarshalYAML() (interface{}, error) {
+	return Redacted, nil
+}
+
+// UnmarshalText stores text as the value of the token.
 func (t *Token) UnmarshalText(text []byte) error {
 	*t = Token(text)
 	return nil
 }
+
+// formatRedacted writes the redacted representation of a value for the provided verb. The Go representation is
+// written for %#v and a quoted representation for %q.
+func formatRedacted(f fmt.State, verb rune, goString string) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		_, _ = fmt.Fprint(f, goString)
+	case verb == 'q':
+		_, _ = fmt.Fprintf(f, "%q", Redacted)
+	default:
+		_, _ = fmt.Fprint(f, Redacted)
+	}
+}
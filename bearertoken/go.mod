@@ -1,5 +1,15 @@
 module github.com/palantir/pkg/bearertoken
 
-require github.com/palantir/pkg v1.1.0
-
 go 1.25.0
+
+require (
+	github.com/palantir/pkg v1.1.0
+	github.com/palantir/pkg/refreshable/v2 v2.3.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,141 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// VerificationKey is a key used to verify the signature of a JWT.
+type VerificationKey struct {
+	// KeyID is matched against the "kid" header parameter of tokens. A key without an ID matches all tokens.
+	KeyID string
+	// Algorithm restricts the key to the provided signing algorithm if it is non-empty.
+	Algorithm string
+	// Key is a []byte secret for HS256, an *rsa.PublicKey for RS256 or an *ecdsa.PublicKey on the P-256 curve for ES256.
+	Key interface{}
+}
+
+// KeySet is a set of keys used to verify the signatures of JWTs.
+type KeySet []VerificationKey
+
+// ParseJWKS parses a JSON Web Key Set document, such as the one served by the JWKS endpoint of an OpenID Connect
+// provider. RSA keys, EC keys on the P-256 curve and symmetric ("oct") keys are supported. Keys of other types, EC keys
+// on other curves and keys whose "use" parameter is not "sig" are ignored.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JWKS: %v", err)
+	}
+	var keys KeySet
+	for i, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d in JWKS: %v", i, err)
+		}
+		if key == nil {
+			continue
+		}
+		keys = append(keys, VerificationKey{
+			KeyID:     jwk.KeyID,
+			Algorithm: jwk.Algorithm,
+			Key:       key,
+		})
+	}
+	return keys, nil
+}
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+
+	// oct
+	K string `json:"k"`
+}
+
+// publicKey returns the key represented by the JWK, or nil if its type or curve is not supported.
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeKeyParam("n", jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeKeyParam("e", jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}, nil
+	case "EC":
+		if jwk.Curve != "P-256" {
+			return nil, nil
+		}
+		x, err := decodeKeyParam("x", jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeKeyParam("y", jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, fmt.Errorf("P-256 coordinates must be 32 bytes")
+		}
+		// validate that the point is on the curve
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, fmt.Errorf("invalid P-256 point: %v", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "oct":
+		return decodeKeyParam("k", jwk.K)
+	default:
+		return nil, nil
+	}
+}
+
+func decodeKeyParam(name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("missing %q parameter", name)
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %q parameter: %v", name, err)
+	}
+	return decoded, nil
+}
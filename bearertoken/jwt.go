@@ -0,0 +1,364 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors returned when parsing or verifying a JWT. Errors returned by the functions in this file wrap one of these
+// errors, so they can be inspected using errors.Is.
+var (
+	ErrMalformedJWT         = errors.New("malformed JWT")
+	ErrUnsupportedAlgorithm = errors.New("unsupported JWT algorithm")
+	ErrKeyNotFound          = errors.New("no key found to verify JWT")
+	ErrInvalidSignature     = errors.New("invalid JWT signature")
+	ErrExpired              = errors.New("JWT is expired")
+	ErrNotYetValid          = errors.New("JWT is not yet valid")
+	ErrInvalidAudience      = errors.New("JWT audience is not accepted")
+	ErrInvalidIssuer        = errors.New("JWT issuer is not accepted")
+)
+
+// Signing algorithms supported by VerifyJWT.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+// JWTHeader is the header of a JSON Web Token.
+type JWTHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// JWTClaims are the registered claims of a JSON Web Token. Time claims are the zero time if they are not set. Use
+// Token.UnmarshalJWTClaims to access other claims.
+type JWTClaims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	ID        string
+}
+
+// UnmarshalJSON unmarshals the registered claims of a JWT payload. The "aud" claim may be a string or an array of
+// strings and the time claims are numeric dates (seconds since the epoch).
+func (c *JWTClaims) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Issuer    string          `json:"iss"`
+		Subject   string          `json:"sub"`
+		Audience  json.RawMessage `json:"aud"`
+		ExpiresAt json.Number     `json:"exp"`
+		NotBefore json.Number     `json:"nbf"`
+		IssuedAt  json.Number     `json:"iat"`
+		ID        string          `json:"jti"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	audience, err := parseAudience(raw.Audience)
+	if err != nil {
+		return err
+	}
+	claims := JWTClaims{
+		Issuer:   raw.Issuer,
+		Subject:  raw.Subject,
+		Audience: audience,
+		ID:       raw.ID,
+	}
+	for _, date := range []struct {
+		name  string
+		value json.Number
+		dest  *time.Time
+	}{
+		{name: "exp", value: raw.ExpiresAt, dest: &claims.ExpiresAt},
+		{name: "nbf", value: raw.NotBefore, dest: &claims.NotBefore},
+		{name: "iat", value: raw.IssuedAt, dest: &claims.IssuedAt},
+	} {
+		if *date.dest, err = parseNumericDate(date.value); err != nil {
+			return fmt.Errorf("invalid %q claim: %v", date.name, err)
+		}
+	}
+	*c = claims
+	return nil
+}
+
+func parseAudience(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(raw, &multiple); err != nil {
+		return nil, fmt.Errorf(`invalid "aud" claim: must be a string or an array of strings`)
+	}
+	return multiple, nil
+}
+
+func parseNumericDate(value json.Number) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseFloat(string(value), 64)
+	if err != nil || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return time.Time{}, fmt.Errorf("%s is not a numeric date", value)
+	}
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*float64(time.Second))), nil
+}
+
+// JWTHeader returns the decoded header of the token, which must be a JWT in compact serialization. The signature of
+// the token is not verified.
+func (t Token) JWTHeader() (JWTHeader, error) {
+	parts, err := t.jwtParts()
+	if err != nil {
+		return JWTHeader{}, err
+	}
+	var header JWTHeader
+	if err := decodeSegment(parts[0], "header", &header); err != nil {
+		return JWTHeader{}, err
+	}
+	return header, nil
+}
+
+// JWTClaims returns the registered claims of the token, which must be a JWT in compact serialization. The signature
+// of the token is not verified and its claims are not validated, so the returned claims must only be used for
+// purposes such as logging or routing. Use VerifyJWT to obtain claims that can be trusted.
+func (t Token) JWTClaims() (JWTClaims, error) {
+	var claims JWTClaims
+	if err := t.UnmarshalJWTClaims(&claims); err != nil {
+		return JWTClaims{}, err
+	}
+	return claims, nil
+}
+
+// UnmarshalJWTClaims unmarshals the claims of the token, which must be a JWT in compact serialization, into v. The
+// signature of the token is not verified.
+func (t Token) UnmarshalJWTClaims(v interface{}) error {
+	parts, err := t.jwtParts()
+	if err != nil {
+		return err
+	}
+	return decodeSegment(parts[1], "claims", v)
+}
+
+func (t Token) jwtParts() ([]string, error) {
+	parts := strings.Split(string(t), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 segments but found %d", ErrMalformedJWT, len(parts))
+	}
+	return parts, nil
+}
+
+func decodeSegment(segment, name string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode %s: %v", ErrMalformedJWT, name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal %s: %v", ErrMalformedJWT, name, err)
+	}
+	return nil
+}
+
+// VerifyParam configures the validation performed by VerifyJWT.
+type VerifyParam func(*verifier)
+
+// ExpectedAudience configures VerifyJWT to require that the "aud" claim of the token contains at least one of the
+// provided audiences.
+func ExpectedAudience(audiences ...string) VerifyParam {
+	return func(v *verifier) {
+		v.audiences = append(v.audiences, audiences...)
+	}
+}
+
+// ExpectedIssuer configures VerifyJWT to require that the "iss" claim of the token is one of the provided issuers.
+func ExpectedIssuer(issuers ...string) VerifyParam {
+	return func(v *verifier) {
+		v.issuers = append(v.issuers, issuers...)
+	}
+}
+
+// Leeway configures the amount of clock skew tolerated when validating the "exp" and "nbf" claims. The default is 0.
+func Leeway(leeway time.Duration) VerifyParam {
+	return func(v *verifier) {
+		v.leeway = leeway
+	}
+}
+
+// Clock configures the function used to get the current time when validating the "exp" and "nbf" claims. The default
+// is time.Now.
+func Clock(now func() time.Time) VerifyParam {
+	return func(v *verifier) {
+		v.now = now
+	}
+}
+
+type verifier struct {
+	audiences []string
+	issuers   []string
+	leeway    time.Duration
+	now       func() time.Time
+}
+
+// VerifyJWT verifies the signature of the token, which must be a JWT in compact serialization signed using HS256,
+// RS256 or ES256, using a key from the provided key set and validates its claims. The token is rejected if it has
+// expired or is not yet valid, or if its audience or issuer do not match those configured using ExpectedAudience and
+// ExpectedIssuer. Returns the registered claims of the token if it is valid. Use UnmarshalJWTClaims to access other
+// claims once the token has been verified.
+//
+// If the header of the token has a "kid" parameter, only keys without an ID or with a matching ID are used. The
+// returned error wraps one of the errors defined by this package, such as ErrExpired or ErrInvalidSignature.
+func (t Token) VerifyJWT(keys KeySet, params ...VerifyParam) (JWTClaims, error) {
+	v := &verifier{
+		now: time.Now,
+	}
+	for _, param := range params {
+		if param != nil {
+			param(v)
+		}
+	}
+
+	header, err := t.JWTHeader()
+	if err != nil {
+		return JWTClaims{}, err
+	}
+	if err := keys.verifySignature(string(t), header); err != nil {
+		return JWTClaims{}, err
+	}
+	claims, err := t.JWTClaims()
+	if err != nil {
+		return JWTClaims{}, err
+	}
+	if err := v.validate(claims); err != nil {
+		return JWTClaims{}, err
+	}
+	return claims, nil
+}
+
+// JWTValidator returns a Validator that verifies tokens using VerifyJWT with the provided keys and parameters. It can
+// be used with NewMiddleware to authenticate requests with JWTs.
+func JWTValidator(keys KeySet, params ...VerifyParam) Validator {
+	return func(ctx context.Context, token Token) error {
+		_, err := token.VerifyJWT(keys, params...)
+		return err
+	}
+}
+
+func (v *verifier) validate(claims JWTClaims) error {
+	now := v.now()
+	if !claims.ExpiresAt.IsZero() && !now.Before(claims.ExpiresAt.Add(v.leeway)) {
+		return fmt.Errorf("%w: expired at %s", ErrExpired, claims.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	if !claims.NotBefore.IsZero() && now.Add(v.leeway).Before(claims.NotBefore) {
+		return fmt.Errorf("%w: valid from %s", ErrNotYetValid, claims.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if len(v.issuers) > 0 && !slices.Contains(v.issuers, claims.Issuer) {
+		return fmt.Errorf("%w: %q", ErrInvalidIssuer, claims.Issuer)
+	}
+	if len(v.audiences) > 0 && !slices.ContainsFunc(claims.Audience, func(audience string) bool {
+		return slices.Contains(v.audiences, audience)
+	}) {
+		return fmt.Errorf("%w: %q", ErrInvalidAudience, claims.Audience)
+	}
+	return nil
+}
+
+// verifySignature verifies the signature of the provided JWT using the keys that match its header.
+func (keys KeySet) verifySignature(jwt string, header JWTHeader) error {
+	switch header.Algorithm {
+	case HS256, RS256, ES256:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, header.Algorithm)
+	}
+	dot := strings.LastIndex(jwt, ".")
+	signingInput, encodedSignature := jwt[:dot], jwt[dot+1:]
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode signature: %v", ErrMalformedJWT, err)
+	}
+	digest := sha256.Sum256([]byte(signingInput))
+
+	found := false
+	for _, key := range keys {
+		if !key.matches(header) {
+			continue
+		}
+		found = true
+		if key.verify(header.Algorithm, []byte(signingInput), digest[:], signature) {
+			return nil
+		}
+	}
+	if !found {
+		if header.KeyID != "" {
+			return fmt.Errorf("%w: no %s key with ID %q", ErrKeyNotFound, header.Algorithm, header.KeyID)
+		}
+		return fmt.Errorf("%w: no %s key", ErrKeyNotFound, header.Algorithm)
+	}
+	return ErrInvalidSignature
+}
+
+func (k VerificationKey) matches(header JWTHeader) bool {
+	if header.KeyID != "" && k.KeyID != "" && header.KeyID != k.KeyID {
+		return false
+	}
+	if k.Algorithm != "" && k.Algorithm != header.Algorithm {
+		return false
+	}
+	switch key := k.Key.(type) {
+	case []byte:
+		return header.Algorithm == HS256
+	case *rsa.PublicKey:
+		return header.Algorithm == RS256
+	case *ecdsa.PublicKey:
+		return header.Algorithm == ES256 && key.Curve == elliptic.P256()
+	default:
+		return false
+	}
+}
+
+func (k VerificationKey) verify(algorithm string, signingInput, digest, signature []byte) bool {
+	switch key := k.Key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		_, _ = mac.Write(signingInput)
+		return hmac.Equal(mac.Sum(nil), signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		// ES256 signatures are the concatenation of the 32-byte big-endian R and S values
+		if len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(key, digest, r, s)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/bearertoken"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTClaimsWithoutVerification(t *testing.T) {
+	token := signJWT(t, bearertoken.HS256, "key-1", []byte("secret"), map[string]interface{}{
+		"sub":   "user",
+		"aud":   "service",
+		"exp":   1700000000.5,
+		"iat":   1600000000,
+		"scope": "read write",
+	})
+
+	header, err := token.JWTHeader()
+	require.NoError(t, err)
+	assert.Equal(t, bearertoken.JWTHeader{Algorithm: "HS256", Type: "JWT", KeyID: "key-1"}, header)
+
+	claims, err := token.JWTClaims()
+	require.NoError(t, err)
+	assert.Equal(t, "user", claims.Subject)
+	assert.Equal(t, []string{"service"}, claims.Audience)
+	assert.True(t, claims.ExpiresAt.Equal(time.Unix(1700000000, int64(500*time.Millisecond))))
+	assert.True(t, claims.IssuedAt.Equal(time.Unix(1600000000, 0)))
+	assert.True(t, claims.NotBefore.IsZero())
+
+	var custom struct {
+		Scope string `json:"scope"`
+	}
+	require.NoError(t, token.UnmarshalJWTClaims(&custom))
+	assert.Equal(t, "read write", custom.Scope)
+
+	for _, malformed := range []bearertoken.Token{"opaque-token", "a.b", "e30.!!!.sig", "e30.bm90LWpzb24.sig"} {
+		_, err := malformed.JWTClaims()
+		assert.ErrorIs(t, err, bearertoken.ErrMalformedJWT, string(malformed))
+	}
+}
+
+func TestVerifyJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	hmacKey := []byte("0123456789abcdef0123456789abcdef")
+	keys := bearertoken.KeySet{
+		{KeyID: "rsa", Key: &rsaKey.PublicKey},
+		{KeyID: "ec", Key: &ecKey.PublicKey},
+		{KeyID: "p384", Key: &p384Key.PublicKey},
+		{Key: hmacKey},
+	}
+
+	now := time.Unix(1700000000, 0)
+	claims := map[string]interface{}{
+		"iss": "issuer",
+		"sub": "user",
+		"aud": []string{"other", "service"},
+		"nbf": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+	}
+	params := []bearertoken.VerifyParam{
+		bearertoken.Clock(func() time.Time { return now }),
+		bearertoken.ExpectedAudience("service"),
+		bearertoken.ExpectedIssuer("issuer"),
+	}
+	for _, tc := range []struct {
+		name  string
+		token bearertoken.Token
+	}{
+		{name: "HS256", token: signJWT(t, bearertoken.HS256, "", hmacKey, claims)},
+		{name: "RS256", token: signJWT(t, bearertoken.RS256, "rsa", rsaKey, claims)},
+		{name: "ES256", token: signJWT(t, bearertoken.ES256, "ec", ecKey, claims)},
+		{name: "ES256 without key ID", token: signJWT(t, bearertoken.ES256, "", ecKey, claims)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			verified, err := tc.token.VerifyJWT(keys, params...)
+			require.NoError(t, err)
+			assert.Equal(t, "user", verified.Subject)
+			assert.NoError(t, bearertoken.JWTValidator(keys, params...)(context.Background(), tc.token))
+		})
+	}
+
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	withClaim := func(name string, value interface{}) map[string]interface{} {
+		modified := make(map[string]interface{}, len(claims))
+		for k, v := range claims {
+			modified[k] = v
+		}
+		modified[name] = value
+		return modified
+	}
+	for _, tc := range []struct {
+		name    string
+		token   bearertoken.Token
+		params  []bearertoken.VerifyParam
+		wantErr error
+	}{
+		{name: "wrong key", token: signJWT(t, bearertoken.RS256, "rsa", otherRSAKey, claims), wantErr: bearertoken.ErrInvalidSignature},
+		{name: "unknown key ID", token: signJWT(t, bearertoken.RS256, "unknown", rsaKey, claims), wantErr: bearertoken.ErrKeyNotFound},
+		{name: "key ID of key with other algorithm", token: signJWT(t, bearertoken.RS256, "ec", rsaKey, claims), wantErr: bearertoken.ErrKeyNotFound},
+		{name: "ES256 key ID of P-384 key", token: signJWT(t, bearertoken.ES256, "p384", ecKey, claims), wantErr: bearertoken.ErrKeyNotFound},
+		{name: "none algorithm", token: signJWT(t, "none", "", nil, claims), wantErr: bearertoken.ErrUnsupportedAlgorithm},
+		{name: "tampered", token: signJWT(t, bearertoken.HS256, "", hmacKey, claims) + "x", wantErr: bearertoken.ErrInvalidSignature},
+		{name: "expired", token: signJWT(t, bearertoken.HS256, "", hmacKey, withClaim("exp", now.Unix())), wantErr: bearertoken.ErrExpired},
+		{name: "not yet valid", token: signJWT(t, bearertoken.HS256, "", hmacKey, withClaim("nbf", now.Add(time.Second).Unix())), wantErr: bearertoken.ErrNotYetValid},
+		{name: "not yet valid within leeway", token: signJWT(t, bearertoken.HS256, "", hmacKey, withClaim("nbf", now.Add(time.Second).Unix())), params: []bearertoken.VerifyParam{bearertoken.Leeway(time.Second)}},
+		{name: "wrong audience", token: signJWT(t, bearertoken.HS256, "", hmacKey, withClaim("aud", "other")), wantErr: bearertoken.ErrInvalidAudience},
+		{name: "wrong issuer", token: signJWT(t, bearertoken.HS256, "", hmacKey, withClaim("iss", "other")), wantErr: bearertoken.ErrInvalidIssuer},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.token.VerifyJWT(keys, append(params, tc.params...)...)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	encode := base64.RawURLEncoding.EncodeToString
+
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "use": "sig", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "hmac", "k": %q},
+		{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "OKP", "kid": "ed25519", "crv": "Ed25519", "x": "AAAA"}
+	]}`,
+		encode(rsaKey.N.Bytes()), encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+		encode(ecKey.X.FillBytes(make([]byte, 32))), encode(ecKey.Y.FillBytes(make([]byte, 32))),
+		encode([]byte("secret")))
+	keys, err := bearertoken.ParseJWKS([]byte(jwks))
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	assert.Equal(t, bearertoken.VerificationKey{KeyID: "rsa", Algorithm: "RS256", Key: &rsaKey.PublicKey}, keys[0])
+	assert.Equal(t, bearertoken.VerificationKey{KeyID: "hmac", Key: []byte("secret")}, keys[2])
+
+	claims := map[string]interface{}{"sub": "user"}
+	for _, token := range []bearertoken.Token{
+		signJWT(t, bearertoken.RS256, "rsa", rsaKey, claims),
+		signJWT(t, bearertoken.ES256, "ec", ecKey, claims),
+		signJWT(t, bearertoken.HS256, "hmac", []byte("secret"), claims),
+	} {
+		_, err := token.VerifyJWT(keys)
+		assert.NoError(t, err)
+	}
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	mixed := fmt.Sprintf(`{"keys": [
+		{"kty": "EC", "kid": "p384", "crv": "P-384", "x": %q, "y": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q}
+	]}`,
+		encode(p384Key.X.FillBytes(make([]byte, 48))), encode(p384Key.Y.FillBytes(make([]byte, 48))),
+		encode(ecKey.X.FillBytes(make([]byte, 32))), encode(ecKey.Y.FillBytes(make([]byte, 32))))
+	keys, err = bearertoken.ParseJWKS([]byte(mixed))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "ec", keys[0].KeyID)
+	_, err = signJWT(t, bearertoken.ES256, "ec", ecKey, claims).VerifyJWT(keys)
+	assert.NoError(t, err)
+
+	_, err = bearertoken.ParseJWKS([]byte(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "AAAA", "y": "AAAA"}]}`))
+	assert.EqualError(t, err, "invalid key 0 in JWKS: P-256 coordinates must be 32 bytes")
+}
+
+// signJWT returns a JWT with the provided claims signed using the provided algorithm and key. The token is unsigned if
+// key is nil.
+func signJWT(t *testing.T, algorithm, keyID string, key interface{}, claims map[string]interface{}) bearertoken.Token {
+	header, err := json.Marshal(bearertoken.JWTHeader{Algorithm: algorithm, Type: "JWT", KeyID: keyID})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch key := key.(type) {
+	case nil:
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		_, _ = mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	default:
+		require.Failf(t, "unsupported key", "%T", key)
+	}
+	return bearertoken.Token(signingInput + "." + base64.RawURLEncoding.EncodeToString(signature))
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrMissingToken is returned by FromRequest if the request does not include a bearer token.
+var ErrMissingToken = errors.New("request does not include a bearer token")
+
+// Validator returns an error if the provided token is not valid.
+type Validator func(ctx context.Context, token Token) error
+
+// MiddlewareParam configures the middleware returned by NewMiddleware.
+type MiddlewareParam func(*middleware)
+
+// CookieName configures the middleware to read the token from the cookie with the provided name if the request does not
+// have an Authorization header.
+func CookieName(name string) MiddlewareParam {
+	return func(m *middleware) {
+		m.cookieName = name
+	}
+}
+
+// OptionalToken configures the middleware to pass requests that do not include a token to the wrapped handler
+// instead of rejecting them. Requests that include an invalid token are still rejected.
+func OptionalToken() MiddlewareParam {
+	return func(m *middleware) {
+		m.optional = true
+	}
+}
+
+// NewMiddleware returns middleware that extracts the bearer token of each request using FromRequest (or from a cookie
+// if CookieName is provided), validates it using the provided validator and stores it in the request context, from
+// which it can be retrieved using FromContext. Requests without a token or with a token that fails validation are
+// rejected with 401 Unauthorized and a "WWW-Authenticate: Bearer" header. The validator may be nil if tokens should
+// not be validated.
+func NewMiddleware(validate Validator, params ...MiddlewareParam) func(http.Handler) http.Handler {
+	m := &middleware{
+		validate: validate,
+	}
+	for _, param := range params {
+		if param != nil {
+			param(m)
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			m.serveHTTP(next, rw, req)
+		})
+	}
+}
+
+type middleware struct {
+	validate   Validator
+	cookieName string
+	optional   bool
+}
+
+func (m *middleware) serveHTTP(next http.Handler, rw http.ResponseWriter, req *http.Request) {
+	token, err := m.token(req)
+	if errors.Is(err, ErrMissingToken) && m.optional {
+		next.ServeHTTP(rw, req)
+		return
+	}
+	if err != nil {
+		unauthorized(rw, "")
+		return
+	}
+	if m.validate != nil {
+		if err := m.validate(req.Context(), token); err != nil {
+			unauthorized(rw, "invalid_token")
+			return
+		}
+	}
+	next.ServeHTTP(rw, req.WithContext(WithToken(req.Context(), token)))
+}
+
+func (m *middleware) token(req *http.Request) (Token, error) {
+	token, err := FromRequest(req)
+	if !errors.Is(err, ErrMissingToken) || m.cookieName == "" {
+		return token, err
+	}
+	cookie, cookieErr := req.Cookie(m.cookieName)
+	if cookieErr != nil || cookie.Value == "" {
+		return "", ErrMissingToken
+	}
+	return Token(cookie.Value), nil
+}
+
+// FromRequest returns the bearer token in the Authorization header of the provided request. Returns ErrMissingToken if
+// the request does not have an Authorization header, or an error if the header does not use the Bearer scheme.
+func FromRequest(req *http.Request) (Token, error) {
+	header := req.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrMissingToken
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", errors.New("authorization header does not use the Bearer scheme")
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrMissingToken
+	}
+	return Token(token), nil
+}
+
+type contextKey struct{}
+
+// WithToken returns a copy of the provided context that stores the provided token.
+func WithToken(ctx context.Context, token Token) context.Context {
+	return context.WithValue(ctx, contextKey{}, token)
+}
+
+// FromContext returns the token stored in the provided context by WithToken or by the middleware returned by
+// NewMiddleware.
+func FromContext(ctx context.Context) (Token, bool) {
+	token, ok := ctx.Value(contextKey{}).(Token)
+	return token, ok
+}
+
+func unauthorized(rw http.ResponseWriter, errorCode string) {
+	challenge := "Bearer"
+	if errorCode != "" {
+		challenge += ` error="` + errorCode + `"`
+	}
+	rw.Header().Set("WWW-Authenticate", challenge)
+	http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/palantir/pkg/bearertoken"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	validate := func(ctx context.Context, token bearertoken.Token) error {
+		if token != "valid" {
+			return errors.New("invalid token")
+		}
+		return nil
+	}
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		token, ok := bearertoken.FromContext(req.Context())
+		if !ok {
+			token = "none"
+		}
+		_, _ = io.WriteString(rw, string(token))
+	})
+
+	for _, tc := range []struct {
+		name             string
+		params           []bearertoken.MiddlewareParam
+		header           string
+		cookie           string
+		wantStatus       int
+		wantBody         string
+		wantAuthenticate string
+	}{
+		{name: "valid header", header: "Bearer valid", wantStatus: http.StatusOK, wantBody: "valid"},
+		{name: "lowercase scheme", header: "bearer valid", wantStatus: http.StatusOK, wantBody: "valid"},
+		{name: "invalid token", header: "Bearer invalid", wantStatus: http.StatusUnauthorized, wantAuthenticate: `Bearer error="invalid_token"`},
+		{name: "basic scheme", header: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized, wantAuthenticate: "Bearer"},
+		{name: "missing token", wantStatus: http.StatusUnauthorized, wantAuthenticate: "Bearer"},
+		{name: "cookie", params: []bearertoken.MiddlewareParam{bearertoken.CookieName("TOKEN")}, cookie: "valid", wantStatus: http.StatusOK, wantBody: "valid"},
+		{name: "header takes precedence over cookie", params: []bearertoken.MiddlewareParam{bearertoken.CookieName("TOKEN")}, header: "Bearer invalid", cookie: "valid", wantStatus: http.StatusUnauthorized, wantAuthenticate: `Bearer error="invalid_token"`},
+		{name: "cookie not configured", cookie: "valid", wantStatus: http.StatusUnauthorized, wantAuthenticate: "Bearer"},
+		{name: "optional missing token", params: []bearertoken.MiddlewareParam{bearertoken.OptionalToken()}, wantStatus: http.StatusOK, wantBody: "none"},
+		{name: "optional invalid token", params: []bearertoken.MiddlewareParam{bearertoken.OptionalToken()}, header: "Bearer invalid", wantStatus: http.StatusUnauthorized, wantAuthenticate: `Bearer error="invalid_token"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "TOKEN", Value: tc.cookie})
+			}
+			rw := httptest.NewRecorder()
+			bearertoken.NewMiddleware(validate, tc.params...)(handler).ServeHTTP(rw, req)
+			assert.Equal(t, tc.wantStatus, rw.Code)
+			assert.Equal(t, tc.wantAuthenticate, rw.Header().Get("WWW-Authenticate"))
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, rw.Body.String())
+			}
+		})
+	}
+}
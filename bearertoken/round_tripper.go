@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxDrainBytes is the maximum number of bytes read from the body of a rejected response before it is closed so that
+// its connection can be reused.
+const maxDrainBytes = 4096
+
+// NewRoundTripper returns an http.RoundTripper that sets the "Authorization: Bearer" header of each request to the
+// token returned by the provided source and sends the request using next.
+//
+// If the server responds with 401 Unauthorized and the source implements Refresher, the source is refreshed and the
+// request is retried once with the refreshed token. Requests with a body are only retried if their GetBody function is
+// set, which is the case for requests created by http.NewRequest with a bytes.Buffer, bytes.Reader or strings.Reader
+// body.
+func NewRoundTripper(next http.RoundTripper, source TokenSource) http.RoundTripper {
+	return &roundTripper{
+		next:   next,
+		source: source,
+	}
+}
+
+type roundTripper struct {
+	next   http.RoundTripper
+	source TokenSource
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token, err := rt.source.Token(ctx)
+	if err != nil {
+		closeRequestBody(req)
+		return nil, fmt.Errorf("failed to get bearer token: %v", err)
+	}
+	resp, err := rt.next.RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	refresher, ok := rt.source.(Refresher)
+	if !ok || !isRewindable(req) {
+		return resp, nil
+	}
+	if err := refresher.Refresh(ctx); err != nil {
+		return resp, nil
+	}
+	refreshed, err := rt.source.Token(ctx)
+	if err != nil || refreshed == token {
+		return resp, nil
+	}
+	retryReq := withToken(req, refreshed)
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retryReq.Body = body
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+	_ = resp.Body.Close()
+	return rt.next.RoundTrip(retryReq)
+}
+
+// withToken returns a shallow copy of req with its Authorization header set to token. The original request is not
+// modified, as required by http.RoundTripper.
+func withToken(req *http.Request, token Token) *http.Request {
+	authReq := req.Clone(req.Context())
+	authReq.Body = req.Body
+	authReq.Header.Set("Authorization", "Bearer "+string(token))
+	return authReq
+}
+
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/bearertoken"
+	"github.com/palantir/pkg/refreshable/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTripperRefreshesOnUnauthorized(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		requests = append(requests, req.Header.Get("Authorization")+" "+string(body))
+		if req.Header.Get("Authorization") != "Bearer new-token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("old-token\n"), 0600))
+	source := bearertoken.NewFileTokenSource(tokenFile, 0)
+	client := &http.Client{Transport: bearertoken.NewRoundTripper(http.DefaultTransport, source)}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("body"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, []string{"Bearer old-token body"}, requests, "token is unchanged, so the request is not retried")
+
+	requests = nil
+	require.NoError(t, os.WriteFile(tokenFile, []byte("new-token\n"), 0600))
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("body"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"Bearer old-token body", "Bearer new-token body"}, requests)
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, bearertoken.Token("new-token"), token)
+}
+
+func TestRoundTripperSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(rw, req.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	token := refreshable.New(bearertoken.Token("first"))
+	client := &http.Client{Transport: bearertoken.NewRoundTripper(http.DefaultTransport, bearertoken.RefreshableTokenSource(token))}
+	assert.Equal(t, "Bearer first", get(t, client, server.URL))
+	token.Update("second")
+	assert.Equal(t, "Bearer second", get(t, client, server.URL))
+
+	client = &http.Client{Transport: bearertoken.NewRoundTripper(http.DefaultTransport, bearertoken.StaticTokenSource("static"))}
+	assert.Equal(t, "Bearer static", get(t, client, server.URL))
+
+	client = &http.Client{Transport: bearertoken.NewRoundTripper(http.DefaultTransport, bearertoken.NewFileTokenSource(filepath.Join(t.TempDir(), "missing"), 0))}
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get bearer token: failed to read token file")
+}
+
+func TestFileTokenSourceRefreshInterval(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("first"), 0600))
+	source := bearertoken.NewFileTokenSource(tokenFile, 50*time.Millisecond)
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, bearertoken.Token("first"), token)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("second"), 0600))
+	token, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, bearertoken.Token("first"), token)
+
+	time.Sleep(50 * time.Millisecond)
+	token, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, bearertoken.Token("second"), token)
+
+	// the previous token is used if the file becomes invalid
+	require.NoError(t, os.WriteFile(tokenFile, nil, 0600))
+	assert.EqualError(t, source.Refresh(context.Background()), "token file "+tokenFile+" is empty")
+	token, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, bearertoken.Token("second"), token)
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
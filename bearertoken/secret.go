@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Secret holds a sensitive value that is redacted when it is printed using the fmt package or marshalled as JSON or
+// YAML. Use Reveal to access the value. Unmarshalling JSON or YAML into a Secret stores the unmarshalled value, so a
+// Secret can be used in configuration structs without the value appearing in logs or diagnostic output.
+//
+// safejson.Marshal and safeyaml.Marshal marshal the value of a Secret when the UnsafeRevealSecrets option is
+// provided.
+type Secret[T any] struct {
+	value    T
+	revealed bool
+}
+
+// NewSecret returns a Secret that holds the provided value.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{
+		value: value,
+	}
+}
+
+// Reveal returns the value of the secret.
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// Unredacted returns a copy of the secret that marshals its value instead of Redacted. It is used by the
+// UnsafeRevealSecrets options of safejson and safeyaml and should not otherwise be needed.
+func (s Secret[T]) Unredacted() interface{} {
+	return Secret[T]{
+		value:    s.value,
+		revealed: true,
+	}
+}
+
+// String returns Redacted. Use Reveal to access the value of the secret.
+func (s Secret[T]) String() string {
+	return Redacted
+}
+
+// GoString returns a redacted Go representation of the secret.
+func (s Secret[T]) GoString() string {
+	return `bearertoken.Secret("` + Redacted + `")`
+}
+
+// Format implements fmt.Formatter so that the secret is redacted for all verbs.
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s.GoString())
+}
+
+// MarshalJSON returns Redacted as a JSON string, or the JSON encoding of the value if the secret was returned by
+// Unredacted.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	if s.revealed {
+		return json.Marshal(s.value)
+	}
+	return json.Marshal(Redacted)
+}
+
+// UnmarshalJSON unmarshals data into the value of the secret.
+func (s *Secret[T]) UnmarshalJSON(data []byte) error {
+	*s = Secret[T]{}
+	return json.Unmarshal(data, &s.value)
+}
+
+// MarshalYAML returns Redacted, or the value if the secret was returned by Unredacted.
+func (s Secret[T]) MarshalYAML() (interface{}, error) {
+	if s.revealed {
+		return s.value, nil
+	}
+	return Redacted, nil
+}
+
+// UnmarshalYAML unmarshals YAML into the value of the secret.
+func (s *Secret[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*s = Secret[T]{}
+	return unmarshal(&s.value)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/palantir/pkg/bearertoken"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRedacted(t *testing.T) {
+	token := bearertoken.Token("secret-token")
+	assert.Equal(t, "secret-token", token.Reveal())
+	for _, format := range []string{"%s", "%v", "%+v", "%d", "%x", "%10s"} {
+		assert.Equal(t, "[REDACTED]", fmt.Sprintf(format, token), format)
+	}
+	assert.Equal(t, `"[REDACTED]"`, fmt.Sprintf("%q", token))
+	assert.Equal(t, `bearertoken.Token("[REDACTED]")`, fmt.Sprintf("%#v", token))
+	assert.Equal(t, `{[REDACTED]}`, fmt.Sprintf("%v", struct{ Token bearertoken.Token }{token}))
+
+	text, err := token.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED]", string(text))
+	yamlOut, err := token.MarshalYAML()
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED]", yamlOut)
+
+	type config struct {
+		Token bearertoken.Token `json:"token"`
+	}
+	var cfg config
+	require.NoError(t, json.Unmarshal([]byte(`{"token":"secret-token"}`), &cfg))
+	assert.Equal(t, "secret-token", cfg.Token.Reveal())
+	out, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, `{"token":"[REDACTED]"}`, string(out))
+}
+
+func TestSecret(t *testing.T) {
+	type config struct {
+		User     string                     `json:"user"`
+		Password bearertoken.Secret[string] `json:"password"`
+	}
+
+	var cfg config
+	require.NoError(t, json.Unmarshal([]byte(`{"user":"admin","password":"hunter2"}`), &cfg))
+	assert.Equal(t, "hunter2", cfg.Password.Reveal())
+	assert.Equal(t, "{admin [REDACTED]}", fmt.Sprintf("%v", cfg))
+	assert.Equal(t, `bearertoken.Secret("[REDACTED]")`, fmt.Sprintf("%#v", cfg.Password))
+
+	out, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, `{"user":"admin","password":"[REDACTED]"}`, string(out))
+
+	yamlOut, err := cfg.Password.MarshalYAML()
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED]", yamlOut)
+
+	var number bearertoken.Secret[int]
+	require.NoError(t, number.UnmarshalYAML(func(v interface{}) error {
+		*(v.(*int)) = 42
+		return nil
+	}))
+	assert.Equal(t, 42, number.Reveal())
+
+	revealed := bearertoken.NewSecret(map[string]int{"a": 1}).Unredacted()
+	out, err = json.Marshal(revealed)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(out))
+	yamlOut, err = revealed.(bearertoken.Secret[map[string]int]).MarshalYAML()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1}, yamlOut)
+	assert.Equal(t, "[REDACTED]", fmt.Sprint(revealed), "revealed secrets are still redacted when printed")
+}
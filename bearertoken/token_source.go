@@ -0,0 +1,110 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bearertoken
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/palantir/pkg/refreshable/v2"
+)
+
+// TokenSource provides the token used to authenticate requests.
+type TokenSource interface {
+	// Token returns the current token.
+	Token(ctx context.Context) (Token, error)
+}
+
+// Refresher is implemented by a TokenSource that can reload its token on demand, for example after a server has
+// rejected the current token.
+type Refresher interface {
+	// Refresh reloads the token so that subsequent calls to Token return the reloaded token.
+	Refresh(ctx context.Context) error
+}
+
+// TokenSourceFunc is an adapter that allows an ordinary function to be used as a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (Token, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (Token, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource that always returns the provided token.
+func StaticTokenSource(token Token) TokenSource {
+	return TokenSourceFunc(func(context.Context) (Token, error) {
+		return token, nil
+	})
+}
+
+// RefreshableTokenSource returns a TokenSource that returns the current value of the provided refreshable.
+func RefreshableTokenSource(token refreshable.Refreshable[Token]) TokenSource {
+	return TokenSourceFunc(func(context.Context) (Token, error) {
+		return token.Current(), nil
+	})
+}
+
+// FileTokenSource is a TokenSource that reads its token from a file. The token is cached and the file is read again
+// once the refresh interval has elapsed or when Refresh is called. Leading and trailing whitespace is removed from the
+// contents of the file.
+type FileTokenSource struct {
+	path            string
+	refreshInterval time.Duration
+
+	mutex    sync.Mutex
+	token    Token
+	loadedAt time.Time
+}
+
+var _ Refresher = (*FileTokenSource)(nil)
+
+// NewFileTokenSource returns a FileTokenSource that reads its token from the file at the provided path. If
+// refreshInterval is 0, the file is only read again when Refresh is called.
+func NewFileTokenSource(path string, refreshInterval time.Duration) *FileTokenSource {
+	return &FileTokenSource{
+		path:            path,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// Token returns the cached token, reading the file if the token has not been loaded or the refresh interval has
+// elapsed. If the file cannot be read and a token was previously loaded, the previous token is returned.
+func (s *FileTokenSource) Token(ctx context.Context) (Token, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.loadedAt.IsZero() && (s.refreshInterval == 0 || time.Since(s.loadedAt) < s.refreshInterval) {
+		return s.token, nil
+	}
+	if err := s.load(); err != nil && s.loadedAt.IsZero() {
+		return "", err
+	}
+	return s.token, nil
+}
+
+// Refresh reads the file. Returns an error if the file cannot be read or is empty, in which case the previous token
+// continues to be used.
+func (s *FileTokenSource) Refresh(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.load()
+}
+
+func (s *FileTokenSource) load() error {
+	contents, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read token file %s: %v", s.path, err)
+	}
+	token := strings.TrimSpace(string(contents))
+	if token == "" {
+		return fmt.Errorf("token file %s is empty", s.path)
+	}
+	s.token = Token(token)
+	s.loadedAt = time.Now()
+	return nil
+}
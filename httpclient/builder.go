@@ -0,0 +1,227 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/palantir/pkg/retry"
+	"github.com/palantir/pkg/tlsconfig"
+)
+
+const (
+	defaultTimeout     = 30 * time.Second
+	defaultMaxAttempts = 3
+)
+
+// RoundTripperFunc is an adapter that allows an ordinary function to be used as an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps an http.RoundTripper to add behavior such as authentication, logging or metrics.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// ClientParam configures a client created by NewClient.
+type ClientParam func(b *clientBuilder) error
+
+type clientBuilder struct {
+	timeout           time.Duration
+	timeoutSet        bool
+	tlsConfig         *tls.Config
+	tlsParams         []tlsconfig.ClientParam
+	http2             bool
+	transportConfig   *TransportConfig
+	transport         http.RoundTripper
+	middlewares       []Middleware
+	retryEnabled      bool
+	retryOptions      []retry.Option
+	retryPolicy       RetryPolicy
+	perAttemptTimeout time.Duration
+}
+
+// NewClient returns an http client configured by the provided parameters. By default, the client uses the transport
+// returned by NewTransporter with a timeout of 30 seconds and does not retry requests.
+//
+// Each request made by the client is handled by the retry logic configured by WithRetry and WithPerAttemptTimeout,
+// which sends every attempt through the middleware configured by WithMiddleware and then through the base transport.
+func NewClient(params ...ClientParam) (*http.Client, error) {
+	b := &clientBuilder{
+		timeout:     defaultTimeout,
+		retryPolicy: DefaultRetryPolicy,
+	}
+	for _, param := range params {
+		if param == nil {
+			continue
+		}
+		if err := param(b); err != nil {
+			return nil, err
+		}
+	}
+
+	transport, err := b.baseTransport()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(b.middlewares) - 1; i >= 0; i-- {
+		transport = b.middlewares[i](transport)
+	}
+	if b.retryEnabled || b.perAttemptTimeout > 0 {
+		retryOptions := []retry.Option{retry.WithMaxAttempts(1)}
+		if b.retryEnabled {
+			retryOptions = append([]retry.Option{retry.WithMaxAttempts(defaultMaxAttempts)}, b.retryOptions...)
+		}
+		transport = &retryRoundTripper{
+			next:              transport,
+			retryEnabled:      b.retryEnabled,
+			policy:            b.retryPolicy,
+			retryOptions:      retryOptions,
+			perAttemptTimeout: b.perAttemptTimeout,
+		}
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   b.timeout,
+	}, nil
+}
+
+func (b *clientBuilder) baseTransport() (http.RoundTripper, error) {
+	if b.transport != nil {
+		if b.tlsConfig != nil || len(b.tlsParams) > 0 || b.http2 || b.transportConfig != nil {
+			return nil, fmt.Errorf("WithTransport cannot be combined with WithTLSConfig, WithTLSParams, WithHTTP2 or WithTransportConfig")
+		}
+		return b.transport, nil
+	}
+	tlsConf := b.tlsConfig
+	if len(b.tlsParams) > 0 {
+		if tlsConf != nil {
+			return nil, fmt.Errorf("WithTLSConfig cannot be combined with WithTLSParams")
+		}
+		var err error
+		if tlsConf, err = tlsconfig.NewClientConfig(b.tlsParams...); err != nil {
+			return nil, fmt.Errorf("failed to create TLS config: %v", err)
+		}
+	}
+	if b.transportConfig != nil {
+		cfg := *b.transportConfig
+		cfg.HTTP2.Enabled = cfg.HTTP2.Enabled || b.http2
+		return cfg.NewTransport(tlsConf)
+	}
+	if b.http2 {
+		return NewHTTP2Transporter(b.timeout, tlsConf)
+	}
+	return NewTransporter(b.timeout, tlsConf), nil
+}
+
+// WithTimeout sets the timeout of the client and of its transport as described by NewHTTPClient. The timeout of the
+// client covers all attempts of a request, including the time spent waiting between retries. If this parameter is not
+// provided, defaults to 30 seconds.
+func WithTimeout(timeout time.Duration) ClientParam {
+	return func(b *clientBuilder) error {
+		b.timeout = timeout
+		b.timeoutSet = true
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the transport.
+func WithTLSConfig(tlsConf *tls.Config) ClientParam {
+	return func(b *clientBuilder) error {
+		b.tlsConfig = tlsConf
+		return nil
+	}
+}
+
+// WithTLSParams sets the TLS configuration of the transport to the configuration returned by
+// tlsconfig.NewClientConfig for the provided parameters.
+func WithTLSParams(params ...tlsconfig.ClientParam) ClientParam {
+	return func(b *clientBuilder) error {
+		b.tlsParams = append(b.tlsParams, params...)
+		return nil
+	}
+}
+
+// WithHTTP2 configures the transport for HTTP/2 connections as described by NewHTTP2Transporter.
+func WithHTTP2() ClientParam {
+	return func(b *clientBuilder) error {
+		b.http2 = true
+		return nil
+	}
+}
+
+// WithTransportConfig configures the transport using TransportConfig.NewTransport instead of NewTransporter. The timeout
+// set by WithTimeout only applies to the client.
+func WithTransportConfig(cfg TransportConfig) ClientParam {
+	return func(b *clientBuilder) error {
+		b.transportConfig = &cfg
+		return nil
+	}
+}
+
+// WithTransport sets the base transport used to send requests. It cannot be combined with the parameters that
+// configure the default transport (WithTLSConfig, WithTLSParams, WithHTTP2 and WithTransportConfig).
+func WithTransport(transport http.RoundTripper) ClientParam {
+	return func(b *clientBuilder) error {
+		if transport == nil {
+			return fmt.Errorf("transport must not be nil")
+		}
+		b.transport = transport
+		return nil
+	}
+}
+
+// WithMiddleware adds middleware that wraps the base transport. Middleware is called for every attempt of a request,
+// and the first middleware provided is the outermost. Can be provided multiple times, in which case the middleware is
+// appended.
+func WithMiddleware(middlewares ...Middleware) ClientParam {
+	return func(b *clientBuilder) error {
+		b.middlewares = append(b.middlewares, middlewares...)
+		return nil
+	}
+}
+
+// WithRetry enables retrying requests that are rejected by the retry policy. The provided options configure the backoff
+// between attempts and are applied after the default option of retry.WithMaxAttempts(3). Requests are never retried
+// after the context of the request is done or the timeout of the client has elapsed.
+//
+// If a retried response includes a Retry-After header, the next attempt is delayed until at least the time specified
+// by the header. If the context of the request expires before then, the response is returned instead.
+//
+// Requests whose body does not set GetBody are only retried if their body is at most 1 MiB long, since the body has to
+// be read into memory to be sent again. See RetryPolicy for when such bodies are read.
+func WithRetry(options ...retry.Option) ClientParam {
+	return func(b *clientBuilder) error {
+		b.retryEnabled = true
+		b.retryOptions = append(b.retryOptions, options...)
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy that determines which attempts are retried when WithRetry is provided. If this
+// parameter is not provided, DefaultRetryPolicy is used.
+func WithRetryPolicy(policy RetryPolicy) ClientParam {
+	return func(b *clientBuilder) error {
+		if policy == nil {
+			return fmt.Errorf("retry policy must not be nil")
+		}
+		b.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithPerAttemptTimeout sets the timeout of each attempt of a request, which includes reading the response body. An
+// attempt that times out is retried according to the retry policy.
+func WithPerAttemptTimeout(timeout time.Duration) ClientParam {
+	return func(b *clientBuilder) error {
+		b.perAttemptTimeout = timeout
+		return nil
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient_test
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpclient"
+	"github.com/palantir/pkg/retry"
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/palantir/pkg/tlsconfig/tlsconfigtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientRetry(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(rw, "ok")
+	}))
+	defer server.Close()
+
+	client, err := httpclient.NewClient(httpclient.WithRetry(retry.WithInitialBackoff(time.Millisecond)))
+	require.NoError(t, err)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	// non-idempotent methods are not retried by default
+	atomic.StoreInt32(&attempts, 0)
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("body"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+
+	// the last response is returned when the attempts are exhausted
+	atomic.StoreInt32(&attempts, -10)
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, int32(-7), atomic.LoadInt32(&attempts))
+}
+
+func TestNewClientRetryRewindsBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			rw.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client, err := httpclient.NewClient(
+		httpclient.WithRetry(retry.WithInitialBackoff(time.Millisecond)),
+		httpclient.WithRetryPolicy(func(req *http.Request, resp *http.Response, err error) bool {
+			return httpclient.IsRetryable(resp, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	// the body of the request is a reader that does not support GetBody
+	req, err := http.NewRequest(http.MethodPost, server.URL, io.NopCloser(strings.NewReader("payload")))
+	require.NoError(t, err)
+	require.Nil(t, req.GetBody)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+}
+
+// trackingReader records whether it has been read from.
+type trackingReader struct {
+	io.Reader
+	read *bool
+}
+
+func (r trackingReader) Read(p []byte) (int, error) {
+	*r.read = true
+	return r.Reader.Read(p)
+}
+
+func TestNewClientRetryDoesNotBufferBodyThatCannotBeRetried(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		params []httpclient.ClientParam
+	}{
+		{name: "non-idempotent method with default policy", params: []httpclient.ClientParam{
+			httpclient.WithRetry(retry.WithInitialBackoff(time.Millisecond)),
+		}},
+		{name: "per-attempt timeout without retries", params: []httpclient.ClientParam{
+			httpclient.WithPerAttemptTimeout(time.Second),
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var read bool
+			var attempts int
+			transport := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				// the body must not have been read into memory before the request is sent
+				assert.False(t, read)
+				_, err := io.Copy(io.Discard, req.Body)
+				require.NoError(t, err)
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody, Request: req}, nil
+			})
+			client, err := httpclient.NewClient(append(tc.params, httpclient.WithTransport(transport))...)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "http://localhost", trackingReader{Reader: strings.NewReader("payload"), read: &read})
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+			assert.Equal(t, 1, attempts)
+			assert.True(t, read)
+		})
+	}
+}
+
+func TestNewClientRetrySendsLargeBodyOnce(t *testing.T) {
+	var bodies []int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, len(body))
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := httpclient.NewClient(
+		httpclient.WithRetry(retry.WithInitialBackoff(time.Millisecond)),
+		httpclient.WithRetryPolicy(func(req *http.Request, resp *http.Response, err error) bool {
+			return httpclient.IsRetryable(resp, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	payload := strings.Repeat("a", 1<<20+1)
+	req, err := http.NewRequest(http.MethodPost, server.URL, io.NopCloser(strings.NewReader(payload)))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, []int{len(payload)}, bodies)
+}
+
+func TestNewClientRetryAfter(t *testing.T) {
+	var attempts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts = append(attempts, time.Now())
+		if len(attempts) == 1 {
+			rw.Header().Set("Retry-After", "1")
+			rw.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	client, err := httpclient.NewClient(httpclient.WithRetry(retry.WithInitialBackoff(time.Millisecond)))
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, attempts, 2)
+	assert.GreaterOrEqual(t, attempts[1].Sub(attempts[0]), time.Second)
+
+	// the response is returned if the Retry-After duration exceeds the deadline of the request
+	attempts = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Len(t, attempts, 1)
+}
+
+func TestNewClientPerAttemptTimeout(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-req.Context().Done()
+			return
+		}
+		_, _ = io.WriteString(rw, "ok")
+	}))
+	defer server.Close()
+
+	client, err := httpclient.NewClient(
+		httpclient.WithRetry(retry.WithInitialBackoff(time.Millisecond)),
+		httpclient.WithPerAttemptTimeout(100*time.Millisecond),
+	)
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+
+	// without retries, the attempt fails with the timeout
+	atomic.StoreInt32(&attempts, 0)
+	client, err = httpclient.NewClient(httpclient.WithPerAttemptTimeout(100 * time.Millisecond))
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewClientMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(rw, strings.Join(req.Header.Values("X-Middleware"), ","))
+	}))
+	defer server.Close()
+
+	middleware := func(name string) httpclient.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Add("X-Middleware", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	client, err := httpclient.NewClient(
+		httpclient.WithMiddleware(middleware("first"), middleware("second")),
+		httpclient.WithMiddleware(middleware("third")),
+	)
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "first,second,third", readBody(t, resp))
+}
+
+func TestNewClientTLSParams(t *testing.T) {
+	ca := tlsconfigtest.NewCA(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(rw, "ok")
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{ca.NewServerCert(t).TLSCertificate()}}
+	server.StartTLS()
+	defer server.Close()
+
+	client, err := httpclient.NewClient(httpclient.WithTLSParams(tlsconfig.ClientRootCAs(ca.CertPoolProvider())))
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", readBody(t, resp))
+
+	_, err = httpclient.NewClient(httpclient.WithTransport(http.DefaultTransport), httpclient.WithHTTP2())
+	assert.EqualError(t, err, "WithTransport cannot be combined with WithTLSConfig, WithTLSParams, WithHTTP2 or WithTransportConfig")
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
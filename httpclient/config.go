@@ -0,0 +1,206 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/http2"
+)
+
+const (
+	defaultDialTimeout           = 10 * time.Second
+	defaultKeepAlive             = 30 * time.Second
+	defaultTLSHandshakeTimeout   = 10 * time.Second
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultExpectContinueTimeout = time.Second
+	defaultMaxIdleConns          = 32
+	defaultMaxIdleConnsPerHost   = 32
+)
+
+// Duration is a time.Duration that is serialized as a string in the format accepted by time.ParseDuration (for
+// example, "10s" or "1m30s").
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// TransportConfig configures the transport created by NewTransport. Fields that are not set use the default value
+// described by their documentation.
+type TransportConfig struct {
+	// DialTimeout is the maximum amount of time a dial waits for a connection to complete. Defaults to 10s.
+	DialTimeout Duration `json:"dial-timeout,omitempty" yaml:"dial-timeout,omitempty"`
+	// KeepAlive is the interval between TCP keep-alive probes. Defaults to 30s. If negative, keep-alive probes are
+	// disabled.
+	KeepAlive Duration `json:"keep-alive,omitempty" yaml:"keep-alive,omitempty"`
+	// TLSHandshakeTimeout is the maximum amount of time to wait for a TLS handshake. Defaults to 10s.
+	TLSHandshakeTimeout Duration `json:"tls-handshake-timeout,omitempty" yaml:"tls-handshake-timeout,omitempty"`
+	// IdleConnTimeout is the maximum amount of time an idle connection remains in the pool before closing itself.
+	// Defaults to 90s.
+	IdleConnTimeout Duration `json:"idle-conn-timeout,omitempty" yaml:"idle-conn-timeout,omitempty"`
+	// ResponseHeaderTimeout is the amount of time to wait for the response headers of a request after its body has been
+	// written. Defaults to no timeout.
+	ResponseHeaderTimeout Duration `json:"response-header-timeout,omitempty" yaml:"response-header-timeout,omitempty"`
+	// ExpectContinueTimeout is the amount of time to wait for a server's first response headers after writing the
+	// headers of a request with an "Expect: 100-continue" header. Defaults to 1s.
+	ExpectContinueTimeout Duration `json:"expect-continue-timeout,omitempty" yaml:"expect-continue-timeout,omitempty"`
+
+	// MaxIdleConns is the maximum number of idle connections across all hosts. Defaults to 32.
+	MaxIdleConns int `json:"max-idle-conns,omitempty" yaml:"max-idle-conns,omitempty"`
+	// MaxIdleConnsPerHost is the maximum number of idle connections to keep per host. Defaults to 32.
+	MaxIdleConnsPerHost int `json:"max-idle-conns-per-host,omitempty" yaml:"max-idle-conns-per-host,omitempty"`
+	// MaxConnsPerHost is the maximum number of connections per host, including connections in the dialing, active and
+	// idle states. Defaults to no limit.
+	MaxConnsPerHost int `json:"max-conns-per-host,omitempty" yaml:"max-conns-per-host,omitempty"`
+	// DisableKeepAlives disables HTTP keep-alives, so each connection is only used for a single request.
+	DisableKeepAlives bool `json:"disable-keep-alives,omitempty" yaml:"disable-keep-alives,omitempty"`
+	// DisableCompression disables requesting compressed responses.
+	DisableCompression bool `json:"disable-compression,omitempty" yaml:"disable-compression,omitempty"`
+
+	// HTTP2 configures HTTP/2 connections.
+	HTTP2 HTTP2Config `json:"http2,omitempty" yaml:"http2,omitempty"`
+	// Proxy configures the proxy used for requests.
+	Proxy ProxyConfig `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	// UnixSocket is the path of a Unix domain socket. If set, all connections are made to the socket regardless of the
+	// host of the request and the proxy configuration must not be set.
+	UnixSocket string `json:"unix-socket,omitempty" yaml:"unix-socket,omitempty"`
+}
+
+// HTTP2Config configures HTTP/2 connections.
+type HTTP2Config struct {
+	// Enabled configures the transport for HTTP/2 connections as described by NewHTTP2Transporter.
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// ReadIdleTimeout is the amount of time after which a health check using a ping frame is carried out if no frame
+	// is received on a connection. Defaults to no health check.
+	ReadIdleTimeout Duration `json:"read-idle-timeout,omitempty" yaml:"read-idle-timeout,omitempty"`
+	// PingTimeout is the amount of time after which a connection is closed if a response to a health check ping is not
+	// received. Defaults to 15s.
+	PingTimeout Duration `json:"ping-timeout,omitempty" yaml:"ping-timeout,omitempty"`
+	// WriteByteTimeout is the amount of time after which a connection is closed if no data can be written to it.
+	// Defaults to no timeout.
+	WriteByteTimeout Duration `json:"write-byte-timeout,omitempty" yaml:"write-byte-timeout,omitempty"`
+}
+
+// ProxyConfig configures the proxy used for requests. By default, the proxy is determined by the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables as described by http.ProxyFromEnvironment.
+type ProxyConfig struct {
+	// URL is the URL of the proxy used for all requests. If set, the environment variables are ignored.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// NoProxy is a list of hosts for which URL is not used, in the format of the NO_PROXY environment variable (for
+	// example, "example.com", ".example.com", "10.0.0.0/8" or "example.com:8080"). Requests to localhost and loopback
+	// addresses never use a proxy.
+	NoProxy []string `json:"no-proxy,omitempty" yaml:"no-proxy,omitempty"`
+	// Disabled disables the proxy, including the proxy configured by the environment variables.
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// NewTransport returns a transport configured by the provided config that uses the provided TLS configuration.
+// Returns an error if the config is invalid.
+func (c TransportConfig) NewTransport(tlsConf *tls.Config) (*http.Transport, error) {
+	proxy, err := c.Proxy.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{
+		Timeout:   durationOrDefault(c.DialTimeout, defaultDialTimeout),
+		KeepAlive: durationOrDefault(c.KeepAlive, defaultKeepAlive),
+	}
+	dialContext := dialer.DialContext
+	if c.UnixSocket != "" {
+		if c.Proxy.URL != "" || len(c.Proxy.NoProxy) > 0 {
+			return nil, fmt.Errorf("proxy configuration cannot be combined with a Unix socket")
+		}
+		proxy = nil
+		socket := c.UnixSocket
+		dialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
+
+	tr := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialContext,
+		TLSClientConfig:       tlsConf,
+		TLSHandshakeTimeout:   durationOrDefault(c.TLSHandshakeTimeout, defaultTLSHandshakeTimeout),
+		IdleConnTimeout:       durationOrDefault(c.IdleConnTimeout, defaultIdleConnTimeout),
+		ResponseHeaderTimeout: time.Duration(c.ResponseHeaderTimeout),
+		ExpectContinueTimeout: durationOrDefault(c.ExpectContinueTimeout, defaultExpectContinueTimeout),
+		MaxIdleConns:          intOrDefault(c.MaxIdleConns, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:   intOrDefault(c.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       c.MaxConnsPerHost,
+		DisableKeepAlives:     c.DisableKeepAlives,
+		DisableCompression:    c.DisableCompression,
+	}
+	if c.HTTP2.Enabled {
+		h2, err := http2.ConfigureTransports(tr)
+		if err != nil {
+			return nil, err
+		}
+		h2.ReadIdleTimeout = time.Duration(c.HTTP2.ReadIdleTimeout)
+		h2.PingTimeout = time.Duration(c.HTTP2.PingTimeout)
+		h2.WriteByteTimeout = time.Duration(c.HTTP2.WriteByteTimeout)
+	}
+	return tr, nil
+}
+
+func (c ProxyConfig) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	switch {
+	case c.Disabled:
+		if c.URL != "" || len(c.NoProxy) > 0 {
+			return nil, fmt.Errorf("proxy URL and no-proxy list cannot be set when the proxy is disabled")
+		}
+		return nil, nil
+	case c.URL == "":
+		if len(c.NoProxy) > 0 {
+			return nil, fmt.Errorf("no-proxy list cannot be set without a proxy URL")
+		}
+		return http.ProxyFromEnvironment, nil
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %v", c.URL, err)
+	}
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  c.URL,
+		HTTPSProxy: c.URL,
+		NoProxy:    strings.Join(c.NoProxy, ","),
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+func durationOrDefault(d Duration, defaultDuration time.Duration) time.Duration {
+	if d == 0 {
+		return defaultDuration
+	}
+	return time.Duration(d)
+}
+
+func intOrDefault(i, defaultInt int) int {
+	if i == 0 {
+		return defaultInt
+	}
+	return i
+}
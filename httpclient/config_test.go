@@ -0,0 +1,151 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient_test
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTransportConfigSerialization(t *testing.T) {
+	const configYAML = `
+dial-timeout: 5s
+idle-conn-timeout: 1m30s
+max-idle-conns-per-host: 8
+http2:
+  enabled: true
+  read-idle-timeout: 30s
+proxy:
+  url: http://proxy:3128
+  no-proxy:
+    - .internal
+`
+	var cfg httpclient.TransportConfig
+	require.NoError(t, yaml.Unmarshal([]byte(configYAML), &cfg))
+	want := httpclient.TransportConfig{
+		DialTimeout:         httpclient.Duration(5 * time.Second),
+		IdleConnTimeout:     httpclient.Duration(90 * time.Second),
+		MaxIdleConnsPerHost: 8,
+		HTTP2: httpclient.HTTP2Config{
+			Enabled:         true,
+			ReadIdleTimeout: httpclient.Duration(30 * time.Second),
+		},
+		Proxy: httpclient.ProxyConfig{
+			URL:     "http://proxy:3128",
+			NoProxy: []string{".internal"},
+		},
+	}
+	assert.Equal(t, want, cfg)
+
+	configJSON, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"dial-timeout": "5s",
+		"idle-conn-timeout": "1m30s",
+		"max-idle-conns-per-host": 8,
+		"http2": {"enabled": true, "read-idle-timeout": "30s"},
+		"proxy": {"url": "http://proxy:3128", "no-proxy": [".internal"]}
+	}`, string(configJSON))
+	var fromJSON httpclient.TransportConfig
+	require.NoError(t, json.Unmarshal(configJSON, &fromJSON))
+	assert.Equal(t, want, fromJSON)
+
+	assert.Error(t, yaml.Unmarshal([]byte("dial-timeout: 5"), &cfg))
+}
+
+func TestTransportConfigNewTransport(t *testing.T) {
+	tr, err := httpclient.TransportConfig{}.NewTransport(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, tr.TLSHandshakeTimeout)
+	assert.Equal(t, 90*time.Second, tr.IdleConnTimeout)
+	assert.Equal(t, time.Duration(0), tr.ResponseHeaderTimeout)
+	assert.Equal(t, 32, tr.MaxIdleConns)
+	assert.Equal(t, 32, tr.MaxIdleConnsPerHost)
+	assert.NotNil(t, tr.Proxy)
+	assert.Nil(t, tr.TLSNextProto)
+
+	tr, err = httpclient.TransportConfig{
+		ResponseHeaderTimeout: httpclient.Duration(time.Second),
+		MaxConnsPerHost:       4,
+		HTTP2:                 httpclient.HTTP2Config{Enabled: true},
+		Proxy:                 httpclient.ProxyConfig{Disabled: true},
+	}.NewTransport(nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, tr.ResponseHeaderTimeout)
+	assert.Equal(t, 4, tr.MaxConnsPerHost)
+	assert.Contains(t, tr.TLSNextProto, "h2")
+	assert.Nil(t, tr.Proxy)
+}
+
+func TestTransportConfigProxy(t *testing.T) {
+	tr, err := httpclient.TransportConfig{
+		Proxy: httpclient.ProxyConfig{URL: "http://proxy:3128", NoProxy: []string{".internal", "10.0.0.0/8"}},
+	}.NewTransport(nil)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		url       string
+		wantProxy string
+	}{
+		{url: "https://example.com/", wantProxy: "http://proxy:3128"},
+		{url: "http://example.com/", wantProxy: "http://proxy:3128"},
+		{url: "https://service.internal/"},
+		{url: "http://10.1.2.3/"},
+		{url: "http://localhost/"},
+	} {
+		req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+		require.NoError(t, err)
+		proxyURL, err := tr.Proxy(req)
+		require.NoError(t, err)
+		if tc.wantProxy == "" {
+			assert.Nil(t, proxyURL, tc.url)
+		} else {
+			require.NotNil(t, proxyURL, tc.url)
+			assert.Equal(t, tc.wantProxy, proxyURL.String(), tc.url)
+		}
+	}
+
+	_, err = httpclient.TransportConfig{Proxy: httpclient.ProxyConfig{NoProxy: []string{".internal"}}}.NewTransport(nil)
+	assert.EqualError(t, err, "no-proxy list cannot be set without a proxy URL")
+	_, err = httpclient.TransportConfig{Proxy: httpclient.ProxyConfig{URL: "http://proxy:3128", Disabled: true}}.NewTransport(nil)
+	assert.EqualError(t, err, "proxy URL and no-proxy list cannot be set when the proxy is disabled")
+}
+
+func TestTransportConfigUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "server.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(rw, req.Host)
+	})}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		_ = server.Close()
+	}()
+
+	client, err := httpclient.NewClient(httpclient.WithTransportConfig(httpclient.TransportConfig{UnixSocket: socket}))
+	require.NoError(t, err)
+	resp, err := client.Get("http://service/")
+	require.NoError(t, err)
+	assert.Equal(t, "service", readBody(t, resp))
+
+	_, err = httpclient.TransportConfig{
+		UnixSocket: socket,
+		Proxy:      httpclient.ProxyConfig{URL: "http://proxy:3128"},
+	}.NewTransport(nil)
+	assert.EqualError(t, err, "proxy configuration cannot be combined with a Unix socket")
+}
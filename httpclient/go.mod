@@ -4,7 +4,27 @@ go 1.25.0
 
 require (
 	github.com/palantir/pkg v1.1.0
+	github.com/palantir/pkg/metrics v1.9.0
+	github.com/palantir/pkg/refreshable/v2 v2.3.0
+	github.com/palantir/pkg/retry v1.3.0
+	github.com/palantir/pkg/tlsconfig v1.5.0
+	github.com/stretchr/testify v1.11.1
 	golang.org/x/net v0.43.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require golang.org/x/text v0.29.0 // indirect
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/palantir/go-metrics v1.1.1 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/text v0.29.0 // indirect
+	software.sslmate.com/src/go-pkcs12 v0.7.3 // indirect
+)
+
+replace (
+	github.com/palantir/pkg/refreshable/v2 => ../refreshable
+	github.com/palantir/pkg/tlsconfig => ../tlsconfig
+)
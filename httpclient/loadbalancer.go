@@ -0,0 +1,339 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable/v2"
+)
+
+const (
+	// HostResponseTimerName is the name of the timer that records the time taken by each host to return response
+	// headers. It is tagged with the host using the key HostTagKey.
+	HostResponseTimerName = "client.host.response"
+	// HostErrorMeterName is the name of the meter that is marked for each request to a host that fails with an error or
+	// a 503 status code.
+	HostErrorMeterName = "client.host.error"
+	// HostInFlightCounterName is the name of the counter whose count is the number of requests to a host that have not
+	// completed. A request completes when its response body is closed.
+	HostInFlightCounterName = "client.host.inflight"
+	// HostHealthyGaugeName is the name of the gauge whose value is 0 if the last request to a host failed and 1
+	// otherwise.
+	HostHealthyGaugeName = "client.host.healthy"
+
+	HostTagKey = "host"
+
+	defaultHostInitialBackoff = time.Second
+	defaultHostMaxBackoff     = 30 * time.Second
+)
+
+// LoadBalancingStrategy determines how a LoadBalancer selects the host for each request.
+type LoadBalancingStrategy int
+
+const (
+	// RoundRobin selects healthy hosts in turn.
+	RoundRobin LoadBalancingStrategy = iota
+	// LeastInFlight selects the healthy host with the fewest in-flight requests. Ties are broken in round-robin order.
+	LeastInFlight
+)
+
+// LoadBalancerParam configures a LoadBalancer.
+type LoadBalancerParam func(lb *LoadBalancer)
+
+// WithStrategy sets the strategy used to select hosts. If this parameter is not provided, defaults to RoundRobin.
+func WithStrategy(strategy LoadBalancingStrategy) LoadBalancerParam {
+	return func(lb *LoadBalancer) {
+		lb.strategy = strategy
+	}
+}
+
+// WithHostBackoff sets the duration for which a host is considered unhealthy after it fails. The duration starts at
+// initialBackoff and doubles with each consecutive failure up to maxBackoff. If this parameter is not provided, defaults
+// to an initial backoff of 1 second and a maximum backoff of 30 seconds.
+func WithHostBackoff(initialBackoff, maxBackoff time.Duration) LoadBalancerParam {
+	return func(lb *LoadBalancer) {
+		lb.initialBackoff = initialBackoff
+		lb.maxBackoff = maxBackoff
+	}
+}
+
+// WithHostMetrics configures the load balancer to record per-host metrics on the provided registry.
+func WithHostMetrics(registry metrics.Registry) LoadBalancerParam {
+	return func(lb *LoadBalancer) {
+		lb.registry = registry
+	}
+}
+
+// LoadBalancer is an http.RoundTripper that distributes requests across multiple base URLs.
+//
+// The scheme and host of each request are replaced by those of the selected base URL, and the path of the base URL is
+// prepended to the path of the request, so requests may be created using any host (for example,
+// "http://service/api/resource"). A host that fails with a connection error or returns a 503 status code is marked
+// unhealthy and is not selected until its backoff has elapsed, unless all hosts are unhealthy. Requests that fail with
+// an error before they are sent to a host, and requests with idempotent methods that fail with an error, are retried
+// on the other hosts. Requests whose body does not set GetBody are only retried if their body is at most 1 MiB long.
+//
+// Hosts track in-flight requests until the response body is closed, so callers must close response bodies for the
+// LeastInFlight strategy and the HostInFlightCounterName counter to be accurate.
+type LoadBalancer struct {
+	next           http.RoundTripper
+	strategy       LoadBalancingStrategy
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	registry       metrics.Registry
+
+	counter atomic.Uint64
+	// mutex guards updates to hosts.
+	mutex sync.Mutex
+	hosts atomic.Pointer[[]*lbHost]
+}
+
+type lbHost struct {
+	baseURL  *url.URL
+	inFlight atomic.Int64
+
+	mutex          sync.Mutex
+	failures       int
+	unhealthyUntil time.Time
+}
+
+// NewLoadBalancer returns a LoadBalancer that sends requests to the provided base URLs using the provided transport.
+func NewLoadBalancer(next http.RoundTripper, baseURLs []string, params ...LoadBalancerParam) (*LoadBalancer, error) {
+	lb := newLoadBalancer(next, params)
+	if err := lb.setBaseURLs(baseURLs); err != nil {
+		return nil, err
+	}
+	return lb, nil
+}
+
+// NewRefreshableLoadBalancer returns a LoadBalancer that sends requests to the base URLs provided by the refreshable
+// using the provided transport. When the base URLs change, hosts that are still present keep their health state and
+// in-flight requests to removed hosts are allowed to complete. Updates that contain no base URLs or an invalid URL are
+// ignored. Returns an error if the current value of the refreshable is invalid.
+//
+// The returned UnsubscribeFunc stops the load balancer from receiving updates.
+func NewRefreshableLoadBalancer(next http.RoundTripper, baseURLs refreshable.Refreshable[[]string], params ...LoadBalancerParam) (*LoadBalancer, refreshable.UnsubscribeFunc, error) {
+	lb := newLoadBalancer(next, params)
+	if err := lb.setBaseURLs(baseURLs.Current()); err != nil {
+		return nil, nil, err
+	}
+	stop := baseURLs.Subscribe(func(urls []string) {
+		_ = lb.setBaseURLs(urls)
+	})
+	return lb, stop, nil
+}
+
+func newLoadBalancer(next http.RoundTripper, params []LoadBalancerParam) *LoadBalancer {
+	lb := &LoadBalancer{
+		next:           next,
+		strategy:       RoundRobin,
+		initialBackoff: defaultHostInitialBackoff,
+		maxBackoff:     defaultHostMaxBackoff,
+		registry:       metrics.NoopRegistry{},
+	}
+	for _, param := range params {
+		if param != nil {
+			param(lb)
+		}
+	}
+	return lb
+}
+
+func (lb *LoadBalancer) setBaseURLs(baseURLs []string) error {
+	if len(baseURLs) == 0 {
+		return fmt.Errorf("at least one base URL must be provided")
+	}
+	parsed := make([]*url.URL, 0, len(baseURLs))
+	for _, baseURL := range baseURLs {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL %q: %v", baseURL, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q: must be an absolute URL", baseURL)
+		}
+		parsed = append(parsed, u)
+	}
+
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	existing := make(map[string]*lbHost)
+	if current := lb.hosts.Load(); current != nil {
+		for _, host := range *current {
+			existing[host.baseURL.String()] = host
+		}
+	}
+	hosts := make([]*lbHost, 0, len(parsed))
+	for _, u := range parsed {
+		host, ok := existing[u.String()]
+		if ok {
+			delete(existing, u.String())
+		} else {
+			host = &lbHost{baseURL: u}
+			lb.registry.Gauge(HostHealthyGaugeName, host.tag()).Update(1)
+		}
+		hosts = append(hosts, host)
+	}
+	lb.hosts.Store(&hosts)
+	for _, removed := range existing {
+		lb.registry.Unregister(HostHealthyGaugeName, removed.tag())
+	}
+	return nil
+}
+
+// RoundTrip sends the request to a host selected by the load balancer.
+func (lb *LoadBalancer) RoundTrip(req *http.Request) (*http.Response, error) {
+	hosts := *lb.hosts.Load()
+	getBody, rewindable, err := rewindableBody(req, len(hosts) > 1)
+	if err != nil {
+		return nil, err
+	}
+	tried := make(map[*lbHost]bool, len(hosts))
+	for {
+		host := lb.selectHost(hosts, tried)
+		tried[host] = true
+		resp, err := lb.send(host, req, getBody)
+		if err == nil {
+			return resp, nil
+		}
+		if !rewindable || req.Context().Err() != nil || len(tried) == len(hosts) || !(IsIdempotent(req.Method) || isDialError(err)) {
+			return nil, err
+		}
+	}
+}
+
+func (lb *LoadBalancer) send(host *lbHost, req *http.Request, getBody func() (io.ReadCloser, error)) (*http.Response, error) {
+	hostReq := req.Clone(req.Context())
+	hostReq.URL.Scheme = host.baseURL.Scheme
+	hostReq.URL.Host = host.baseURL.Host
+	hostReq.URL.Path = strings.TrimSuffix(host.baseURL.Path, "/") + req.URL.Path
+	if req.URL.RawPath != "" {
+		hostReq.URL.RawPath = strings.TrimSuffix(host.baseURL.EscapedPath(), "/") + req.URL.RawPath
+	}
+	hostReq.Host = ""
+	if getBody != nil {
+		body, err := getBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get request body: %v", err)
+		}
+		hostReq.Body = body
+	}
+
+	tag := host.tag()
+	inFlight := lb.registry.Counter(HostInFlightCounterName, tag)
+	host.inFlight.Add(1)
+	inFlight.Inc(1)
+	done := func() {
+		host.inFlight.Add(-1)
+		inFlight.Dec(1)
+	}
+
+	start := time.Now()
+	resp, err := lb.next.RoundTrip(hostReq)
+	lb.registry.Timer(HostResponseTimerName, tag).UpdateSince(start)
+	switch {
+	case err != nil:
+		done()
+		if req.Context().Err() == nil {
+			lb.markFailure(host)
+		}
+		return nil, err
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		lb.markFailure(host)
+	default:
+		lb.markSuccess(host)
+	}
+	resp.Body = &onCloseBody{ReadCloser: resp.Body, onClose: done}
+	return resp, nil
+}
+
+// selectHost returns the host that should receive the next attempt of a request. Hosts that have already been tried
+// are only selected if every host has been tried, and unhealthy hosts are only selected if there are no healthy hosts.
+func (lb *LoadBalancer) selectHost(hosts []*lbHost, tried map[*lbHost]bool) *lbHost {
+	now := time.Now()
+	var healthy, unhealthy []*lbHost
+	for _, host := range hosts {
+		if tried[host] {
+			continue
+		}
+		if host.isHealthy(now) {
+			healthy = append(healthy, host)
+		} else {
+			unhealthy = append(unhealthy, host)
+		}
+	}
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = unhealthy
+	}
+	if len(candidates) == 0 {
+		candidates = hosts
+	}
+
+	offset := int(lb.counter.Add(1) % uint64(len(candidates)))
+	if lb.strategy != LeastInFlight {
+		return candidates[offset]
+	}
+	var selected *lbHost
+	for i := range candidates {
+		host := candidates[(offset+i)%len(candidates)]
+		if selected == nil || host.inFlight.Load() < selected.inFlight.Load() {
+			selected = host
+		}
+	}
+	return selected
+}
+
+func (lb *LoadBalancer) markFailure(host *lbHost) {
+	lb.registry.Meter(HostErrorMeterName, host.tag()).Mark(1)
+
+	host.mutex.Lock()
+	defer host.mutex.Unlock()
+	backoff := lb.initialBackoff << min(host.failures, 30)
+	if backoff > lb.maxBackoff || backoff <= 0 {
+		backoff = lb.maxBackoff
+	}
+	host.failures++
+	host.unhealthyUntil = time.Now().Add(backoff)
+	lb.registry.Gauge(HostHealthyGaugeName, host.tag()).Update(0)
+}
+
+func (lb *LoadBalancer) markSuccess(host *lbHost) {
+	host.mutex.Lock()
+	defer host.mutex.Unlock()
+	if host.failures == 0 {
+		return
+	}
+	host.failures = 0
+	host.unhealthyUntil = time.Time{}
+	lb.registry.Gauge(HostHealthyGaugeName, host.tag()).Update(1)
+}
+
+func (h *lbHost) isHealthy(now time.Time) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return !now.Before(h.unhealthyUntil)
+}
+
+func (h *lbHost) tag() metrics.Tag {
+	return metrics.NewTagWithFallbackValue(HostTagKey, h.baseURL.Host, "unknown")
+}
+
+// isDialError returns whether err occurred while establishing a connection, in which case the request was not sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpclient"
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBalancerRoundRobin(t *testing.T) {
+	servers := []string{
+		startNamedServer(t, "a") + "/base",
+		startNamedServer(t, "b") + "/base/",
+		startNamedServer(t, "c"),
+	}
+	lb, err := httpclient.NewLoadBalancer(http.DefaultTransport, servers)
+	require.NoError(t, err)
+	client := &http.Client{Transport: lb}
+
+	counts := make(map[string]int)
+	for i := 0; i < 6; i++ {
+		resp, err := client.Get("http://service/resource")
+		require.NoError(t, err)
+		counts[readBody(t, resp)]++
+	}
+	assert.Equal(t, map[string]int{"a /base/resource": 2, "b /base/resource": 2, "c /resource": 2}, counts)
+}
+
+func TestLoadBalancerFailover(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	lb, err := httpclient.NewLoadBalancer(
+		http.DefaultTransport,
+		[]string{closedServerURL(t), startNamedServer(t, "a")},
+		httpclient.WithHostMetrics(registry),
+		httpclient.WithHostBackoff(time.Hour, time.Hour),
+	)
+	require.NoError(t, err)
+	client := &http.Client{Transport: lb}
+
+	// non-idempotent requests are also retried on connection errors
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest(method, "http://service/resource", strings.NewReader("body"))
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			assert.Equal(t, "a /resource", readBody(t, resp))
+		}
+	}
+
+	// the closed host is unhealthy, so it only failed once
+	var errorCount int64
+	healthy := make(map[string]int64)
+	registry.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		switch name {
+		case httpclient.HostErrorMeterName:
+			errorCount += value.Values()["count"].(int64)
+		case httpclient.HostHealthyGaugeName:
+			healthy[tags.ToMap()[httpclient.HostTagKey]] = value.Values()["value"].(int64)
+		}
+	})
+	assert.Equal(t, int64(1), errorCount)
+	var healthyValues []int64
+	for _, value := range healthy {
+		healthyValues = append(healthyValues, value)
+	}
+	assert.ElementsMatch(t, []int64{0, 1}, healthyValues)
+}
+
+func TestLoadBalancerUnavailable(t *testing.T) {
+	unavailable := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	lb, err := httpclient.NewLoadBalancer(http.DefaultTransport, []string{unavailable.URL, startNamedServer(t, "a")}, httpclient.WithHostBackoff(time.Hour, time.Hour))
+	require.NoError(t, err)
+	client := &http.Client{Transport: lb}
+
+	var statuses []int
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get("http://service/")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		statuses = append(statuses, resp.StatusCode)
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusOK, http.StatusOK}, statuses)
+}
+
+func TestLoadBalancerLeastInFlight(t *testing.T) {
+	lb, err := httpclient.NewLoadBalancer(
+		http.DefaultTransport,
+		[]string{startNamedServer(t, "a"), startNamedServer(t, "b")},
+		httpclient.WithStrategy(httpclient.LeastInFlight),
+	)
+	require.NoError(t, err)
+	client := &http.Client{Transport: lb}
+
+	// the body of the first response is not closed, so its host has a request in flight
+	open, err := client.Get("http://service/")
+	require.NoError(t, err)
+	defer func() {
+		_ = open.Body.Close()
+	}()
+	openBody := make([]byte, 1)
+	_, err = io.ReadFull(open.Body, openBody)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get("http://service/")
+		require.NoError(t, err)
+		assert.NotEqual(t, string(openBody), readBody(t, resp)[:1])
+	}
+}
+
+func TestRefreshableLoadBalancer(t *testing.T) {
+	a, b := startNamedServer(t, "a"), startNamedServer(t, "b")
+	baseURLs := refreshable.New([]string{a})
+	lb, stop, err := httpclient.NewRefreshableLoadBalancer(http.DefaultTransport, baseURLs)
+	require.NoError(t, err)
+	defer stop()
+	client := &http.Client{Transport: lb}
+
+	resp, err := client.Get("http://service/")
+	require.NoError(t, err)
+	assert.Equal(t, "a /", readBody(t, resp))
+
+	baseURLs.Update([]string{b})
+	resp, err = client.Get("http://service/")
+	require.NoError(t, err)
+	assert.Equal(t, "b /", readBody(t, resp))
+
+	// invalid updates are ignored
+	baseURLs.Update(nil)
+	resp, err = client.Get("http://service/")
+	require.NoError(t, err)
+	assert.Equal(t, "b /", readBody(t, resp))
+
+	_, _, err = httpclient.NewRefreshableLoadBalancer(http.DefaultTransport, refreshable.New([]string{"/relative"}))
+	assert.EqualError(t, err, `invalid base URL "/relative": must be an absolute URL`)
+}
+
+// startNamedServer starts a server that responds with its name and the path of the request and returns its URL.
+func startNamedServer(t *testing.T, name string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(rw, name+" "+req.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// closedServerURL returns the URL of an address that refuses connections.
+func closedServerURL(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return "http://" + addr
+}
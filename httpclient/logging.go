@@ -0,0 +1,213 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RedactedValue replaces the values of redacted headers in logged and recorded requests and responses.
+const RedactedValue = "[REDACTED]"
+
+// defaultRedactedHeaders are the headers whose values are redacted by LoggingMiddleware and Recorder.
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RequestLog describes a request sent through the middleware returned by LoggingMiddleware.
+type RequestLog struct {
+	Method string
+	// URL is the URL of the request with any password replaced by "xxxxx".
+	URL string
+	// StatusCode is the status code of the response, or 0 if the request failed.
+	StatusCode int
+	// Duration is the time between sending the request and receiving the headers of the response (and the logged
+	// portion of the body, if bodies are logged).
+	Duration time.Duration
+	// Err is the error returned by the next round tripper, if any.
+	Err error
+
+	// RequestHeader and ResponseHeader are only set if LogHeaders is provided. Redacted headers are included with
+	// their values replaced by RedactedValue.
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+	// RequestBody and ResponseBody are only set if LogBodies is provided, and contain at most the configured number of
+	// bytes of each body.
+	RequestBody  []byte
+	ResponseBody []byte
+}
+
+// RequestLogger is called by the middleware returned by LoggingMiddleware once for each request.
+type RequestLogger func(ctx context.Context, log RequestLog)
+
+// SlogRequestLogger returns a RequestLogger that logs each request as a single message at the provided level using
+// the provided logger.
+func SlogRequestLogger(logger *slog.Logger, level slog.Level) RequestLogger {
+	return func(ctx context.Context, log RequestLog) {
+		attrs := []slog.Attr{
+			slog.String("method", log.Method),
+			slog.String("url", log.URL),
+			slog.Duration("duration", log.Duration),
+		}
+		if log.Err != nil {
+			attrs = append(attrs, slog.String("error", log.Err.Error()))
+		} else {
+			attrs = append(attrs, slog.Int("status", log.StatusCode))
+		}
+		if log.RequestHeader != nil {
+			attrs = append(attrs, slog.Any("requestHeader", log.RequestHeader))
+		}
+		if log.ResponseHeader != nil {
+			attrs = append(attrs, slog.Any("responseHeader", log.ResponseHeader))
+		}
+		if log.RequestBody != nil {
+			attrs = append(attrs, slog.String("requestBody", string(log.RequestBody)))
+		}
+		if log.ResponseBody != nil {
+			attrs = append(attrs, slog.String("responseBody", string(log.ResponseBody)))
+		}
+		logger.LogAttrs(ctx, level, "HTTP request", attrs...)
+	}
+}
+
+// LoggingParam configures the middleware returned by LoggingMiddleware.
+type LoggingParam func(*loggingMiddleware)
+
+// LogHeaders configures the middleware to log the headers of requests and responses.
+func LogHeaders() LoggingParam {
+	return func(m *loggingMiddleware) {
+		m.headers = true
+	}
+}
+
+// LogBodies configures the middleware to log at most maxBytes of the body of each request and response. The logged
+// portion of a response body is read before the response is returned, so logging the bodies of streaming responses
+// may delay them.
+func LogBodies(maxBytes int) LoggingParam {
+	return func(m *loggingMiddleware) {
+		m.maxBodyBytes = maxBytes
+	}
+}
+
+// RedactHeaders configures additional headers whose values are redacted when headers are logged. The Authorization,
+// Proxy-Authorization, Cookie and Set-Cookie headers are always redacted.
+func RedactHeaders(names ...string) LoggingParam {
+	return func(m *loggingMiddleware) {
+		m.redactedHeaders = append(m.redactedHeaders, names...)
+	}
+}
+
+// LoggingMiddleware returns Middleware that calls log with the method, URL, status and latency of each request that it
+// handles. Headers and bodies are only logged if LogHeaders and LogBodies are provided. Bodies that are logged are
+// still sent and returned in full.
+func LoggingMiddleware(log RequestLogger, params ...LoggingParam) Middleware {
+	m := &loggingMiddleware{
+		log:             log,
+		redactedHeaders: defaultRedactedHeaders,
+	}
+	for _, param := range params {
+		if param != nil {
+			param(m)
+		}
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return m.roundTrip(next, req)
+		})
+	}
+}
+
+type loggingMiddleware struct {
+	log             RequestLogger
+	headers         bool
+	maxBodyBytes    int
+	redactedHeaders []string
+}
+
+func (m *loggingMiddleware) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	entry := RequestLog{
+		Method: req.Method,
+		URL:    req.URL.Redacted(),
+	}
+	if m.headers {
+		entry.RequestHeader = redactHeader(req.Header, m.redactedHeaders)
+	}
+	if m.maxBodyBytes > 0 && req.Body != nil && req.Body != http.NoBody {
+		prefix, body := peekBody(req.Body, m.maxBodyBytes)
+		entry.RequestBody = prefix
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		entry.Duration = time.Since(start)
+		entry.Err = err
+		m.log(req.Context(), entry)
+		return resp, err
+	}
+	if m.maxBodyBytes > 0 && resp.Body != nil && resp.Body != http.NoBody {
+		entry.ResponseBody, resp.Body = peekBody(resp.Body, m.maxBodyBytes)
+	}
+	entry.Duration = time.Since(start)
+	entry.StatusCode = resp.StatusCode
+	if m.headers {
+		entry.ResponseHeader = redactHeader(resp.Header, m.redactedHeaders)
+	}
+	m.log(req.Context(), entry)
+	return resp, nil
+}
+
+// peekBody reads at most maxBytes from body and returns the bytes that were read along with a body that returns the
+// full content of the original body and closes it when closed.
+func peekBody(body io.ReadCloser, maxBytes int) ([]byte, io.ReadCloser) {
+	prefix, err := io.ReadAll(io.LimitReader(body, int64(maxBytes)))
+	rest := io.Reader(body)
+	if err != nil {
+		// return the error to the reader of the body once the prefix has been read
+		rest = &errReader{err: err}
+	}
+	return prefix, &readCloser{
+		Reader: io.MultiReader(bytes.NewReader(prefix), rest),
+		Closer: body,
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// redactHeader returns a copy of header with the values of the provided headers replaced by RedactedValue.
+func redactHeader(header http.Header, redacted []string) http.Header {
+	header = header.Clone()
+	if header == nil {
+		return http.Header{}
+	}
+	for _, name := range redacted {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		redactedValues := make([]string, len(values))
+		for i := range redactedValues {
+			redactedValues[i] = RedactedValue
+		}
+		header[http.CanonicalHeaderKey(name)] = redactedValues
+	}
+	return header
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// RecorderMode determines whether a Recorder sends requests and records them or replays previously recorded exchanges.
+type RecorderMode int
+
+const (
+	// RecordMode sends requests using the next round tripper and saves each exchange to the file of the Recorder.
+	RecordMode RecorderMode = iota
+	// ReplayMode responds to requests with the exchanges saved in the file of the Recorder without sending them.
+	ReplayMode
+)
+
+// Exchange is a request and its response as saved by a Recorder.
+type Exchange struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request saved by a Recorder. The values of redacted headers are replaced by RedactedValue.
+type RecordedRequest struct {
+	Method string       `json:"method"`
+	URL    string       `json:"url"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   RecordedBody `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response saved by a Recorder. The values of redacted headers are replaced by RedactedValue.
+type RecordedResponse struct {
+	StatusCode int          `json:"statusCode"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       RecordedBody `json:"body,omitempty"`
+}
+
+// RecordedBody is the body of a recorded request or response. Bodies that are valid UTF-8 are saved as JSON strings so
+// that recordings can be read and edited; other bodies are saved as an object with a single "base64" field.
+type RecordedBody []byte
+
+type base64Body struct {
+	Base64 string `json:"base64"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b RecordedBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(base64Body{Base64: base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *RecordedBody) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var encoded base64Body
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+		if err != nil {
+			return fmt.Errorf("invalid base64 body: %v", err)
+		}
+		*b = decoded
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*b = RecordedBody(s)
+	return nil
+}
+
+type recording struct {
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// RecorderParam configures a Recorder.
+type RecorderParam func(*Recorder)
+
+// RecorderRedactHeaders configures additional headers whose values are redacted in recorded exchanges. The
+// Authorization, Proxy-Authorization, Cookie and Set-Cookie headers are always redacted.
+func RecorderRedactHeaders(names ...string) RecorderParam {
+	return func(r *Recorder) {
+		r.redactedHeaders = append(r.redactedHeaders, names...)
+	}
+}
+
+// Recorder saves the requests sent through it and their responses to a JSON file and replays them, so that code that
+// depends on an HTTP server can be tested without it. Recordings are made once against a real server using RecordMode
+// and then checked in and replayed by tests using ReplayMode.
+//
+// Use the Wrap method as Middleware, for example using WithMiddleware(recorder.Wrap). Recorder is safe for concurrent
+// use.
+type Recorder struct {
+	path            string
+	mode            RecorderMode
+	redactedHeaders []string
+
+	mu        sync.Mutex
+	exchanges []Exchange
+	// replayed records which exchanges have been used to respond to a request in ReplayMode
+	replayed []bool
+}
+
+// NewRecorder returns a Recorder that saves exchanges to or replays exchanges from the file at path. In RecordMode, the
+// file is overwritten once the first exchange is recorded and rewritten after each subsequent exchange. In ReplayMode,
+// the file is read immediately and an error is returned if it cannot be read.
+func NewRecorder(path string, mode RecorderMode, params ...RecorderParam) (*Recorder, error) {
+	r := &Recorder{
+		path:            path,
+		mode:            mode,
+		redactedHeaders: defaultRedactedHeaders,
+	}
+	for _, param := range params {
+		if param != nil {
+			param(r)
+		}
+	}
+	switch mode {
+	case RecordMode:
+	case ReplayMode:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recorded exchanges: %v", err)
+		}
+		var rec recording
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded exchanges in %s: %v", path, err)
+		}
+		r.exchanges = rec.Exchanges
+		r.replayed = make([]bool, len(rec.Exchanges))
+	default:
+		return nil, fmt.Errorf("unknown recorder mode %d", mode)
+	}
+	return r, nil
+}
+
+// Exchanges returns the exchanges that have been recorded, or that can be replayed.
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+// Wrap returns an http.RoundTripper that records or replays requests depending on the mode of the Recorder. In
+// ReplayMode, next is never called: a request is answered by the first exchange that has not been replayed yet and
+// whose method, URL and body match the request, and an error is returned if there is none.
+//
+// In RecordMode, the bodies of requests and responses are read in full so that they can be saved. Requests that fail
+// with an error are not recorded.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if r.mode == ReplayMode {
+			return r.replay(req)
+		}
+		return r.record(next, req)
+	})
+}
+
+func (r *Recorder) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(resp.Body)
+	if resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	exchange := Exchange{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.Redacted(),
+			Header: r.recordedHeader(req.Header),
+			Body:   reqBody,
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.recordedHeader(resp.Header),
+			Body:       respBody,
+		},
+	}
+	if err := r.save(exchange); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save(exchange Exchange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, exchange)
+	data, err := json.MarshalIndent(recording{Exchanges: r.exchanges}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recorded exchanges: %v", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to save recorded exchanges: %v", err)
+	}
+	return nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+	url := req.URL.Redacted()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, exchange := range r.exchanges {
+		if r.replayed[i] || exchange.Request.Method != req.Method || exchange.Request.URL != url || !bytes.Equal(exchange.Request.Body, body) {
+			continue
+		}
+		r.replayed[i] = true
+		header := exchange.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", exchange.Response.StatusCode, http.StatusText(exchange.Response.StatusCode)),
+			StatusCode:    exchange.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(exchange.Response.Body)),
+			ContentLength: int64(len(exchange.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded exchange matches request %s %s", req.Method, url)
+}
+
+func (r *Recorder) recordedHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	return redactHeader(header, r.redactedHeaders)
+}
+
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil || body == http.NoBody {
+		return nil, nil
+	}
+	return io.ReadAll(body)
+}
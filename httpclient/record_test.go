@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/palantir/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		switch req.URL.Path {
+		case "/binary":
+			_, _ = rw.Write([]byte{0xff, 0x00, 0xfe})
+		default:
+			rw.Header().Set("Set-Cookie", "session=secret")
+			_, _ = io.WriteString(rw, req.Method+" "+string(body))
+		}
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "exchanges.json")
+
+	recorder, err := httpclient.NewRecorder(path, httpclient.RecordMode)
+	require.NoError(t, err)
+	client, err := httpclient.NewClient(httpclient.WithMiddleware(recorder.Wrap))
+	require.NoError(t, err)
+
+	send := func(client *http.Client) []string {
+		var bodies []string
+		for _, body := range []string{"first", "second"} {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/echo", strings.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer token")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			bodies = append(bodies, readBody(t, resp))
+		}
+		resp, err := client.Get(server.URL + "/binary")
+		require.NoError(t, err)
+		return append(bodies, readBody(t, resp))
+	}
+	want := []string{"POST first", "POST second", "\xff\x00\xfe"}
+	assert.Equal(t, want, send(client))
+	assert.Equal(t, 3, requests)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "token")
+	assert.NotContains(t, string(data), "secret")
+	assert.Contains(t, string(data), `"body": "POST first"`)
+	assert.Contains(t, string(data), `"base64": "/wD+"`)
+
+	replayer, err := httpclient.NewRecorder(path, httpclient.ReplayMode)
+	require.NoError(t, err)
+	assert.Equal(t, recorder.Exchanges(), replayer.Exchanges())
+	client, err = httpclient.NewClient(httpclient.WithMiddleware(replayer.Wrap))
+	require.NoError(t, err)
+	assert.Equal(t, want, send(client))
+	assert.Equal(t, 3, requests, "replayed requests are not sent")
+
+	_, err = client.Get(server.URL + "/binary")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded exchange matches request GET "+server.URL+"/binary")
+}
+
+func TestNewRecorderReplayMissingFile(t *testing.T) {
+	_, err := httpclient.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), httpclient.ReplayMode)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read recorded exchanges")
+}
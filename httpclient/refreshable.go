@@ -0,0 +1,202 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/palantir/pkg/refreshable/v2"
+)
+
+// ClientConfig configures a client created by NewRefreshableClient.
+type ClientConfig struct {
+	// Timeout is the timeout of each request made by the client, including all of its attempts and reading the
+	// response body. Defaults to 30s. If negative, requests do not time out.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Transport configures the transport of the client.
+	Transport TransportConfig `json:"transport,omitempty" yaml:"transport,omitempty"`
+}
+
+// NewRefreshableClient returns an http client whose timeout and transport are configured by the provided refreshable
+// config and whose TLS configuration is provided by tlsConf, which may be nil if the default TLS configuration should
+// be used. The provided parameters configure the client as described by NewClient, except that they cannot configure
+// the timeout or transport: an error is returned if WithTimeout or WithTransport is provided, since the timeout and
+// transport are determined by the config.
+//
+// When the config or TLS configuration changes, the client switches to a new transport as described by
+// NewRefreshableTransport and new requests use the new timeout. Returns an error if the current config is invalid.
+// The returned UnsubscribeFunc stops the client from receiving updates.
+func NewRefreshableClient(config refreshable.Refreshable[ClientConfig], tlsConf refreshable.Refreshable[*tls.Config], params ...ClientParam) (*http.Client, refreshable.UnsubscribeFunc, error) {
+	transportConfig := refreshable.View(config, func(cfg ClientConfig) TransportConfig {
+		return cfg.Transport
+	})
+	transport, stop, err := NewRefreshableTransport(transportConfig, tlsConf)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := NewClient(append(params, rejectTimeoutAndTransport, WithTransport(transport))...)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+	client.Transport = &timeoutRoundTripper{
+		next: client.Transport,
+		timeout: func() time.Duration {
+			return durationOrDefault(config.Current().Timeout, defaultTimeout)
+		},
+	}
+	client.Timeout = 0
+	return client, stop, nil
+}
+
+// rejectTimeoutAndTransport is a ClientParam that returns an error if the parameters that precede it configure the
+// timeout or transport of the client, which are configured by the ClientConfig of a refreshable client.
+func rejectTimeoutAndTransport(b *clientBuilder) error {
+	if b.timeoutSet {
+		return fmt.Errorf("WithTimeout cannot be used with NewRefreshableClient: the timeout is configured by ClientConfig.Timeout")
+	}
+	if b.transport != nil {
+		return fmt.Errorf("WithTransport cannot be used with NewRefreshableClient: the transport is configured by ClientConfig.Transport")
+	}
+	return nil
+}
+
+// RefreshableTransport is an http.RoundTripper that sends requests using a transport that is rebuilt when its
+// configuration changes. New requests use the most recently built transport, while requests that are in flight when
+// the configuration changes complete using the transport that they started on. The idle connections of a replaced
+// transport are closed immediately, and its idle connections are closed again when its last in-flight request
+// completes. Connections that are returned to the idle pool after that point are closed by the transport once they
+// exceed its idle connection timeout.
+//
+// A request completes when its response body is closed, so callers must close response bodies for the connections of
+// replaced transports to be closed.
+type RefreshableTransport struct {
+	current atomic.Pointer[transportGeneration]
+}
+
+// NewRefreshableTransport returns a RefreshableTransport whose transport is created by TransportConfig.NewTransport
+// using the provided refreshable config and TLS configuration. tlsConf may be nil if the default TLS configuration
+// should be used. Updates that produce an invalid config are ignored and the previous transport continues to be used.
+// Returns an error if the current config is invalid.
+//
+// The returned UnsubscribeFunc stops the transport from receiving updates.
+func NewRefreshableTransport(config refreshable.Refreshable[TransportConfig], tlsConf refreshable.Refreshable[*tls.Config]) (*RefreshableTransport, refreshable.UnsubscribeFunc, error) {
+	if tlsConf == nil {
+		tlsConf = refreshable.New[*tls.Config](nil)
+	}
+	type transportInputs struct {
+		config  TransportConfig
+		tlsConf *tls.Config
+	}
+	inputs, stopMerge := refreshable.Merge(config, tlsConf, func(cfg TransportConfig, tlsConf *tls.Config) transportInputs {
+		return transportInputs{config: cfg, tlsConf: tlsConf}
+	})
+
+	initial := inputs.Current()
+	transport, err := initial.config.NewTransport(initial.tlsConf)
+	if err != nil {
+		stopMerge()
+		return nil, nil, err
+	}
+	rt := &RefreshableTransport{}
+	rt.current.Store(&transportGeneration{transport: transport})
+
+	// Subscribe calls the consumer with the current value, which was used to build the initial transport
+	initialized := false
+	stopSubscribe := inputs.Subscribe(func(in transportInputs) {
+		if !initialized {
+			initialized = true
+			return
+		}
+		transport, err := in.config.NewTransport(in.tlsConf)
+		if err != nil {
+			return
+		}
+		rt.current.Swap(&transportGeneration{transport: transport}).retire()
+	})
+	return rt, func() {
+		stopSubscribe()
+		stopMerge()
+	}, nil
+}
+
+// RoundTrip sends the request using the current transport.
+func (rt *RefreshableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	gen := rt.current.Load()
+	gen.acquire()
+	resp, err := gen.transport.RoundTrip(req)
+	if err != nil {
+		gen.release()
+		return nil, err
+	}
+	resp.Body = &onCloseBody{ReadCloser: resp.Body, onClose: gen.release}
+	return resp, nil
+}
+
+// CloseIdleConnections closes the idle connections of the current transport.
+func (rt *RefreshableTransport) CloseIdleConnections() {
+	rt.current.Load().transport.CloseIdleConnections()
+}
+
+// transportGeneration is a transport built from a single configuration and the number of requests in flight on it.
+type transportGeneration struct {
+	transport *http.Transport
+
+	mutex    sync.Mutex
+	inFlight int
+	retired  bool
+}
+
+func (g *transportGeneration) acquire() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.inFlight++
+}
+
+func (g *transportGeneration) release() {
+	g.mutex.Lock()
+	g.inFlight--
+	closeIdle := g.retired && g.inFlight == 0
+	g.mutex.Unlock()
+	if closeIdle {
+		g.transport.CloseIdleConnections()
+	}
+}
+
+// retire marks the transport as replaced and closes its idle connections. Connections that are in use are closed
+// when the last request in flight completes.
+func (g *transportGeneration) retire() {
+	g.mutex.Lock()
+	g.retired = true
+	g.mutex.Unlock()
+	g.transport.CloseIdleConnections()
+}
+
+// timeoutRoundTripper applies a timeout that may change between requests to each request.
+type timeoutRoundTripper struct {
+	next    http.RoundTripper
+	timeout func() time.Duration
+}
+
+func (rt *timeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := rt.timeout()
+	if timeout <= 0 {
+		return rt.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := rt.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &onCloseBody{ReadCloser: resp.Body, onClose: cancel}
+	return resp, nil
+}
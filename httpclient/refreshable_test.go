@@ -0,0 +1,138 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient_test
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpclient"
+	"github.com/palantir/pkg/refreshable/v2"
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/palantir/pkg/tlsconfig/tlsconfigtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshableClientSwapsTransport(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			rw.WriteHeader(http.StatusOK)
+			rw.(http.Flusher).Flush()
+			<-release
+		}
+		_, _ = io.WriteString(rw, req.RemoteAddr)
+	}))
+	defer server.Close()
+
+	config := refreshable.New(httpclient.ClientConfig{})
+	client, stop, err := httpclient.NewRefreshableClient(config, nil)
+	require.NoError(t, err)
+	defer stop()
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	firstAddr := readBody(t, resp)
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, firstAddr, readBody(t, resp), "connection should be reused")
+
+	// a request that is in flight when the config changes completes on the old transport
+	slow, err := client.Get(server.URL + "/slow")
+	require.NoError(t, err)
+	config.Update(httpclient.ClientConfig{Transport: httpclient.TransportConfig{MaxIdleConnsPerHost: 1}})
+
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	newAddr := readBody(t, resp)
+	assert.NotEqual(t, firstAddr, newAddr, "new requests should use a new connection")
+
+	close(release)
+	slowBody := readBody(t, slow)
+	assert.NotEqual(t, newAddr, slowBody)
+
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, newAddr, readBody(t, resp))
+
+	// invalid updates are ignored
+	config.Update(httpclient.ClientConfig{Transport: httpclient.TransportConfig{Proxy: httpclient.ProxyConfig{NoProxy: []string{"invalid"}}}})
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, newAddr, readBody(t, resp))
+}
+
+func TestRefreshableClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-req.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	config := refreshable.New(httpclient.ClientConfig{Timeout: httpclient.Duration(50 * time.Millisecond)})
+	client, stop, err := httpclient.NewRefreshableClient(config, nil)
+	require.NoError(t, err)
+	defer stop()
+
+	_, err = client.Get(server.URL)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	config.Update(httpclient.ClientConfig{Timeout: httpclient.Duration(time.Second)})
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+}
+
+func TestNewRefreshableClientRejectsTimeoutAndTransport(t *testing.T) {
+	config := refreshable.New(httpclient.ClientConfig{})
+	_, _, err := httpclient.NewRefreshableClient(config, nil, httpclient.WithTimeout(time.Second))
+	assert.EqualError(t, err, "WithTimeout cannot be used with NewRefreshableClient: the timeout is configured by ClientConfig.Timeout")
+
+	_, _, err = httpclient.NewRefreshableClient(config, nil, httpclient.WithTransport(http.DefaultTransport))
+	assert.EqualError(t, err, "WithTransport cannot be used with NewRefreshableClient: the transport is configured by ClientConfig.Transport")
+}
+
+func TestRefreshableClientTLS(t *testing.T) {
+	ca := tlsconfigtest.NewCA(t)
+	otherCA := tlsconfigtest.NewCA(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{ca.NewServerCert(t).TLSCertificate()}}
+	server.StartTLS()
+	defer server.Close()
+
+	newTLSConfig := func(ca *tlsconfigtest.CA) *tls.Config {
+		cfg, err := tlsconfig.NewClientConfig(tlsconfig.ClientRootCAs(ca.CertPoolProvider()))
+		require.NoError(t, err)
+		return cfg
+	}
+	tlsConf := refreshable.New(newTLSConfig(otherCA))
+	client, stop, err := httpclient.NewRefreshableClient(refreshable.New(httpclient.ClientConfig{}), tlsConf)
+	require.NoError(t, err)
+	defer stop()
+
+	_, err = client.Get(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+
+	tlsConf.Update(newTLSConfig(ca))
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+}
+
+func TestNewRefreshableTransportInvalidConfig(t *testing.T) {
+	_, _, err := httpclient.NewRefreshableTransport(refreshable.New(httpclient.TransportConfig{
+		Proxy: httpclient.ProxyConfig{NoProxy: []string{"invalid"}},
+	}), nil)
+	assert.EqualError(t, err, "no-proxy list cannot be set without a proxy URL")
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/palantir/pkg/retry"
+)
+
+const (
+	// maxDrainBytes is the maximum number of bytes read from the body of a response that is discarded before a retry
+	// so that its connection can be reused.
+	maxDrainBytes = 4096
+
+	// maxBufferedBodyBytes is the maximum size of a request body without GetBody that is read into memory so that the
+	// request can be retried. Larger bodies are sent once without retrying.
+	maxBufferedBodyBytes = 1 << 20
+)
+
+// errBufferBodyCheck is provided to the retry policy to determine whether a request body should be buffered.
+var errBufferBodyCheck = errors.New("httpclient: checking whether the request could be retried")
+
+// RetryPolicy returns whether an attempt of a request should be retried. resp and err are the result of the attempt;
+// exactly one of them is non-nil.
+//
+// Before the first attempt of a request whose body does not set GetBody, the policy is also called with a nil
+// response and a non-nil error to determine whether the request could be retried. The body is only read into memory so
+// that it can be sent again if the policy returns true; otherwise the request is sent once without retrying.
+type RetryPolicy func(req *http.Request, resp *http.Response, err error) bool
+
+// DefaultRetryPolicy retries requests with idempotent methods (GET, HEAD, OPTIONS, TRACE, PUT and DELETE) whose attempt
+// failed with an error or returned a 429, 502, 503 or 504 status code.
+func DefaultRetryPolicy(req *http.Request, resp *http.Response, err error) bool {
+	return IsIdempotent(req.Method) && IsRetryable(resp, err)
+}
+
+// IsIdempotent returns whether the provided method is idempotent as defined by RFC 9110.
+func IsIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable returns whether the result of an attempt indicates a transient failure: an error, or a 429, 502, 503 or
+// 504 status code.
+func IsRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+type retryRoundTripper struct {
+	next              http.RoundTripper
+	retryEnabled      bool
+	policy            RetryPolicy
+	retryOptions      []retry.Option
+	perAttemptTimeout time.Duration
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	couldRetry := rt.retryEnabled && rt.policy(req, nil, errBufferBodyCheck)
+	getBody, rewindable, err := rewindableBody(req, couldRetry)
+	if err != nil {
+		return nil, err
+	}
+	if !rewindable {
+		// the body can only be sent once
+		return rt.attempt(req, getBody)
+	}
+	ctx := req.Context()
+
+	var (
+		resp       *http.Response
+		respTime   time.Time
+		attemptErr error
+	)
+	for r := retry.Start(ctx, rt.retryOptions...); r.Next(); {
+		if resp != nil {
+			// the previous attempt is being retried: wait for its Retry-After duration (which includes the time spent
+			// in backoff) unless doing so would exceed the deadline of the request, then discard it
+			if wait := retryAfter(resp) - time.Since(respTime); wait > 0 {
+				if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+					return resp, nil
+				}
+				if err := sleep(ctx, wait); err != nil {
+					drainAndClose(resp.Body)
+					return nil, err
+				}
+			}
+			drainAndClose(resp.Body)
+		}
+		resp, attemptErr = rt.attempt(req, getBody)
+		respTime = time.Now()
+		if ctx.Err() != nil || !rt.policy(req, resp, attemptErr) {
+			return resp, attemptErr
+		}
+	}
+	if resp == nil && attemptErr == nil {
+		// the context was done before the first attempt
+		return nil, ctx.Err()
+	}
+	return resp, attemptErr
+}
+
+func (rt *retryRoundTripper) attempt(req *http.Request, getBody func() (io.ReadCloser, error)) (*http.Response, error) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if rt.perAttemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), rt.perAttemptTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+	attemptReq := req.Clone(ctx)
+	if getBody != nil {
+		body, err := getBody()
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to get request body: %v", err)
+		}
+		attemptReq.Body = body
+	}
+	resp, err := rt.next.RoundTrip(attemptReq)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &onCloseBody{ReadCloser: resp.Body, onClose: cancel}
+	return resp, nil
+}
+
+// rewindableBody returns a function that returns the body of the provided request for each attempt, or nil if the
+// request does not have a body, and whether the body can be sent more than once. If the request does not set GetBody,
+// its body is only read into memory if couldRetry is true and the body is at most maxBufferedBodyBytes long.
+func rewindableBody(req *http.Request, couldRetry bool) (func() (io.ReadCloser, error), bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true, nil
+	}
+	if req.GetBody != nil {
+		_ = req.Body.Close()
+		return req.GetBody, true, nil
+	}
+	if !couldRetry {
+		return nil, false, nil
+	}
+	prefix, err := io.ReadAll(io.LimitReader(req.Body, maxBufferedBodyBytes+1))
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, false, fmt.Errorf("failed to read request body: %v", err)
+	}
+	if len(prefix) > maxBufferedBodyBytes {
+		body := &readCloser{
+			Reader: io.MultiReader(bytes.NewReader(prefix), req.Body),
+			Closer: req.Body,
+		}
+		return func() (io.ReadCloser, error) {
+			return body, nil
+		}, false, nil
+	}
+	_ = req.Body.Close()
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(prefix)), nil
+	}, true, nil
+}
+
+// retryAfter returns the duration specified by the Retry-After header of the provided response, or 0 if the header is
+// not set or is invalid. The header may be either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, maxDrainBytes)
+	_ = body.Close()
+}
+
+// onCloseBody calls onClose the first time the body of a response is closed.
+type onCloseBody struct {
+	io.ReadCloser
+	onClose func()
+	once    sync.Once
+}
+
+func (b *onCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.onClose)
+	return err
+}
@@ -2,7 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package httpserver provides functions that are useful for HTTP servers. These functions are meant to be used
-// primarily in tests. Provides functions for determining an available port and checking to see when a server is "ready"
-// based on the response provided by an endpoint.
+// Package httpserver provides functions that are useful for HTTP servers. Run starts a server that shuts down
+// gracefully when the process is terminated and HealthRegistry serves the liveness and readiness checks registered by
+// its components. The remaining functions are meant to be used primarily in tests: they determine an available port
+// and check to see when a server is "ready" based on the response provided by an endpoint.
 package httpserver
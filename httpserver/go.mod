@@ -4,6 +4,8 @@ go 1.25.0
 
 require (
 	github.com/palantir/pkg v1.1.0
+	github.com/palantir/pkg/refreshable/v2 v2.3.0
+	github.com/palantir/pkg/retry v1.3.0
 	github.com/stretchr/testify v1.11.1
 )
 
@@ -12,3 +14,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/palantir/pkg/refreshable/v2 => ../refreshable
@@ -0,0 +1,268 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/palantir/pkg/refreshable/v2"
+)
+
+const defaultCheckTimeout = 5 * time.Second
+
+// CheckKind determines which health endpoints a check contributes to.
+type CheckKind int
+
+const (
+	// Liveness checks report whether the process is functioning. A failing liveness check typically causes the process
+	// to be restarted, so it should only fail if the process cannot recover on its own.
+	Liveness CheckKind = iota
+	// Readiness checks report whether the process can serve traffic. A failing readiness check typically causes traffic
+	// to be routed elsewhere until the check passes.
+	Readiness
+)
+
+// HealthState is the state of a health check or of a set of health checks.
+type HealthState string
+
+const (
+	HealthStateHealthy   HealthState = "HEALTHY"
+	HealthStateUnhealthy HealthState = "UNHEALTHY"
+)
+
+// CheckFunc returns a non-nil error if the component that it checks is unhealthy. The error message is included in the
+// health report.
+type CheckFunc func(ctx context.Context) error
+
+// HealthReport is the aggregated result of a set of health checks. Its status is healthy if all of its checks are
+// healthy.
+type HealthReport struct {
+	Status HealthState            `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// CheckResult is the result of a single health check.
+type CheckResult struct {
+	Status  HealthState `json:"status"`
+	Message string      `json:"message,omitempty"`
+}
+
+// HealthRegistry is a set of named health checks registered by the components of a server. It is safe for concurrent
+// use.
+type HealthRegistry struct {
+	mutex  sync.RWMutex
+	checks map[string]*healthCheck
+}
+
+// NewHealthRegistry returns an empty HealthRegistry.
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		checks: make(map[string]*healthCheck),
+	}
+}
+
+// Register registers a check with the provided name and kind. Returns an error if a check with the same name is
+// already registered. Each check has a timeout of 5 seconds unless CheckTimeoutParam is provided, and its result is
+// not cached unless CheckCacheParam is provided.
+func (r *HealthRegistry) Register(name string, kind CheckKind, check CheckFunc, params ...CheckParam) error {
+	c := &healthCheck{
+		kind:    kind,
+		check:   check,
+		timeout: defaultCheckTimeout,
+	}
+	for _, p := range params {
+		if p != nil {
+			p.configCheck(c)
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.checks[name]; ok {
+		return fmt.Errorf("health check %q is already registered", name)
+	}
+	r.checks[name] = c
+	return nil
+}
+
+// Unregister removes the check with the provided name if it is registered.
+func (r *HealthRegistry) Unregister(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.checks, name)
+}
+
+// Check runs the checks of the provided kind concurrently and returns the aggregated report. Readiness reports also
+// include liveness checks, since a process that is not live cannot serve traffic.
+func (r *HealthRegistry) Check(ctx context.Context, kind CheckKind) HealthReport {
+	r.mutex.RLock()
+	checks := make(map[string]*healthCheck, len(r.checks))
+	for name, check := range r.checks {
+		if check.kind == kind || kind == Readiness {
+			checks[name] = check
+		}
+	}
+	r.mutex.RUnlock()
+
+	report := HealthReport{
+		Status: HealthStateHealthy,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+	var reportMutex sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := check.run(ctx)
+			reportMutex.Lock()
+			defer reportMutex.Unlock()
+			report.Checks[name] = result
+			if result.Status != HealthStateHealthy {
+				report.Status = HealthStateUnhealthy
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// Handler returns an http.Handler that runs the checks of the provided kind and responds with the report encoded as
+// JSON. The response status is 200 OK if all checks are healthy and 503 Service Unavailable otherwise.
+func (r *HealthRegistry) Handler(kind CheckKind) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		report := r.Check(req.Context(), kind)
+		status := http.StatusOK
+		if report.Status != HealthStateHealthy {
+			status = http.StatusServiceUnavailable
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		_ = json.NewEncoder(rw).Encode(report)
+	})
+}
+
+// ValidationCheck returns a CheckFunc that reports the result of the most recent validation of the provided
+// refreshable, so that a component whose configuration fails validation is reported as unhealthy even though it
+// continues to use its last valid configuration.
+func ValidationCheck[T any](validated refreshable.Validated[T]) CheckFunc {
+	return func(context.Context) error {
+		_, err := validated.Validation()
+		return err
+	}
+}
+
+// DrainingCheck returns a CheckFunc that fails once the provided server has started shutting down. It is typically
+// registered as a readiness check so that load balancers stop routing traffic to the server during its drain period.
+func DrainingCheck(server *Server) CheckFunc {
+	return func(context.Context) error {
+		if server.Draining() {
+			return errors.New("server is shutting down")
+		}
+		return nil
+	}
+}
+
+// CheckParam configures a health check registered with HealthRegistry.Register.
+type CheckParam interface {
+	configCheck(*healthCheck)
+}
+
+type checkParam func(*healthCheck)
+
+func (p checkParam) configCheck(c *healthCheck) {
+	p(c)
+}
+
+// CheckTimeoutParam configures the maximum duration of a check. A check that does not complete in time is reported as
+// unhealthy. The default is 5 seconds.
+func CheckTimeoutParam(timeout time.Duration) CheckParam {
+	return checkParam(func(c *healthCheck) {
+		c.timeout = timeout
+	})
+}
+
+// CheckCacheParam configures the result of a check to be reused for the provided duration, which avoids running
+// expensive checks on every request to the health endpoint.
+func CheckCacheParam(ttl time.Duration) CheckParam {
+	return checkParam(func(c *healthCheck) {
+		c.cacheTTL = ttl
+	})
+}
+
+type healthCheck struct {
+	kind     CheckKind
+	check    CheckFunc
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mutex     sync.Mutex
+	result    CheckResult
+	checkedAt time.Time
+	// inFlight is the run of the check that is in progress, if any.
+	inFlight *checkRun
+}
+
+// checkRun is a single run of a check whose result is shared by all callers that request it while it is in progress.
+type checkRun struct {
+	done   chan struct{}
+	result CheckResult
+}
+
+// run returns the cached result of the check if it has not expired, or runs the check otherwise. If the check is
+// already running, run waits for and returns the result of that run rather than running the check again. The mutex of
+// the check is not held while the check runs, so a slow check does not block callers that can use the cached result.
+func (c *healthCheck) run(ctx context.Context) CheckResult {
+	c.mutex.Lock()
+	if c.cacheTTL > 0 && !c.checkedAt.IsZero() && time.Since(c.checkedAt) < c.cacheTTL {
+		defer c.mutex.Unlock()
+		return c.result
+	}
+	if inFlight := c.inFlight; inFlight != nil {
+		c.mutex.Unlock()
+		<-inFlight.done
+		return inFlight.result
+	}
+	inFlight := &checkRun{done: make(chan struct{})}
+	c.inFlight = inFlight
+	c.mutex.Unlock()
+
+	inFlight.result = c.runCheck(ctx)
+
+	c.mutex.Lock()
+	c.result = inFlight.result
+	c.checkedAt = time.Now()
+	c.inFlight = nil
+	c.mutex.Unlock()
+	close(inFlight.done)
+	return inFlight.result
+}
+
+// runCheck runs the check in a separate goroutine so that a check that ignores its context does not delay the report
+// beyond its timeout. The check is not canceled when ctx is, so that a result that may be cached or shared with other
+// callers only depends on the check and its timeout and not on the request that triggered it.
+func (c *healthCheck) runCheck(ctx context.Context) CheckResult {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not complete within %s", c.timeout)
+	}
+	if err != nil {
+		return CheckResult{Status: HealthStateUnhealthy, Message: err.Error()}
+	}
+	return CheckResult{Status: HealthStateHealthy}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpserver"
+	"github.com/palantir/pkg/refreshable/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthRegistryHandler(t *testing.T) {
+	registry := httpserver.NewHealthRegistry()
+	var dbConnected atomic.Bool
+	require.NoError(t, registry.Register("process", httpserver.Liveness, func(context.Context) error {
+		return nil
+	}))
+	require.NoError(t, registry.Register("database", httpserver.Readiness, func(context.Context) error {
+		if !dbConnected.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+	assert.EqualError(t, registry.Register("process", httpserver.Readiness, nil), `health check "process" is already registered`)
+
+	rw := httptest.NewRecorder()
+	registry.Handler(httpserver.Liveness).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"status":"HEALTHY","checks":{"process":{"status":"HEALTHY"}}}`, rw.Body.String())
+
+	rw = httptest.NewRecorder()
+	registry.Handler(httpserver.Readiness).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	var report httpserver.HealthReport
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &report))
+	assert.Equal(t, httpserver.HealthReport{
+		Status: httpserver.HealthStateUnhealthy,
+		Checks: map[string]httpserver.CheckResult{
+			"process":  {Status: httpserver.HealthStateHealthy},
+			"database": {Status: httpserver.HealthStateUnhealthy, Message: "connection refused"},
+		},
+	}, report)
+
+	dbConnected.Store(true)
+	assert.Equal(t, httpserver.HealthStateHealthy, registry.Check(context.Background(), httpserver.Readiness).Status)
+
+	registry.Unregister("database")
+	assert.Len(t, registry.Check(context.Background(), httpserver.Readiness).Checks, 1)
+}
+
+func TestHealthCheckTimeoutAndCache(t *testing.T) {
+	registry := httpserver.NewHealthRegistry()
+	require.NoError(t, registry.Register("slow", httpserver.Readiness, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, httpserver.CheckTimeoutParam(20*time.Millisecond)))
+	var calls int32
+	require.NoError(t, registry.Register("cached", httpserver.Readiness, func(context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}, httpserver.CheckCacheParam(time.Hour)))
+
+	start := time.Now()
+	report := registry.Check(context.Background(), httpserver.Readiness)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, httpserver.CheckResult{Status: httpserver.HealthStateUnhealthy, Message: "check did not complete within 20ms"}, report.Checks["slow"])
+
+	registry.Check(context.Background(), httpserver.Readiness)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestHealthCheckCanceledRequest(t *testing.T) {
+	registry := httpserver.NewHealthRegistry()
+	var calls int32
+	require.NoError(t, registry.Register("cached", httpserver.Readiness, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return ctx.Err()
+	}, httpserver.CheckCacheParam(time.Hour)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, httpserver.HealthStateHealthy, registry.Check(ctx, httpserver.Readiness).Status)
+	assert.Equal(t, httpserver.HealthStateHealthy, registry.Check(context.Background(), httpserver.Readiness).Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestHealthCheckSharesInFlightRun(t *testing.T) {
+	registry := httpserver.NewHealthRegistry()
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, registry.Register("slow", httpserver.Readiness, func(context.Context) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return errors.New("failed")
+	}))
+
+	reports := make(chan httpserver.HealthReport, 5)
+	go func() {
+		reports <- registry.Check(context.Background(), httpserver.Readiness)
+	}()
+	<-started
+	for i := 0; i < 4; i++ {
+		go func() {
+			reports <- registry.Check(context.Background(), httpserver.Readiness)
+		}()
+	}
+	// give the other callers time to wait for the run that is in progress
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < 5; i++ {
+		report := <-reports
+		assert.Equal(t, httpserver.CheckResult{Status: httpserver.HealthStateUnhealthy, Message: "failed"}, report.Checks["slow"])
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestValidationCheck(t *testing.T) {
+	config := refreshable.New(1)
+	validated, stop, err := refreshable.Validate(context.Background(), config, func(_ context.Context, i int) error {
+		if i < 0 {
+			return errors.New("must not be negative")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	check := httpserver.ValidationCheck(validated)
+	assert.NoError(t, check(context.Background()))
+	config.Update(-1)
+	assert.EqualError(t, check(context.Background()), "must not be negative")
+	assert.Equal(t, 1, validated.Unvalidated(), "the last valid value continues to be used")
+	config.Update(2)
+	assert.NoError(t, check(context.Background()))
+}
+
+func TestDrainingCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	server, err := httpserver.Run(ctx, http.NotFoundHandler(), httpserver.AddressParam("127.0.0.1:0"))
+	require.NoError(t, err)
+	check := httpserver.DrainingCheck(server)
+	assert.NoError(t, check(context.Background()))
+	cancel()
+	require.NoError(t, server.Wait())
+	assert.EqualError(t, check(context.Background()), "server is shutting down")
+}
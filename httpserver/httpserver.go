@@ -5,18 +5,21 @@
 package httpserver
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/palantir/pkg/retry"
 )
 
 // AvailablePort returns a best-effort determination of an available port. Does so by opening a TCP listener on
 // localhost, determining the port used by that listener, closing the listener and returning the address that was used
 // by the listener. This is best-effort because there is no way to guarantee that another process will not take the port
-// between the time when the listener is closed and the returned port is used by the caller.
+// between the time when the listener is closed and the returned port is used by the caller. Tests should use
+// httpservertest.Listen, which returns a listener that is already bound, or httpservertest.Start instead.
 func AvailablePort() (port int, rErr error) {
 	l, err := net.Listen("tcp", "127.0.0.1:0")
 	defer func() {
@@ -47,9 +50,9 @@ func URLReady(url string, params ...ReadyParam) <-chan bool {
 }
 
 // Ready returns a channel that is sent "true" when the provided readyCall returns a nil error and a response that
-// returns "true" when provided to readyResp. The readyCall is invoked once every tick duration until it either returns
-// a nil error and readyResp returns true for the response or the timeout duration is reached, in which case "false" is
-// sent on the channel.
+// returns "true" when provided to readyResp. The readyCall is invoked immediately and then once every tick duration until
+// it either returns a nil error and readyResp returns true for the response or the timeout duration is reached, in
+// which case "false" is sent on the channel. Use WaitReady to determine why a server did not become ready.
 //
 // readyCall should by a function that returns quickly. At most one readyCall will be running at a particular time.
 //
@@ -74,47 +77,19 @@ func Ready(readyCall func() (*http.Response, error), params ...ReadyParam) <-cha
 		p.config(cfg)
 	}
 
-	once := &sync.Once{}
-	done := make(chan struct{})
-
-	ready := make(chan bool)
+	// the channel is buffered so that the goroutine does not leak if the caller stops waiting
+	ready := make(chan bool, 1)
 	go func() {
-		timeout := time.NewTimer(cfg.timeout)
-		defer timeout.Stop()
-
-		// start a separate goroutine with the ticker. Done so that the possibly expensive action will not
-		// block the timeout.
-		go func() {
-			ticker := time.NewTicker(cfg.tickDuration)
-			defer ticker.Stop()
-			for {
-				select {
-				case <-done:
-					return
-				case <-ticker.C:
-					if resp, err := readyCall(); err == nil && cfg.readyResp(resp) {
-						once.Do(func() {
-							ready <- true
-							close(done)
-						})
-					}
-				}
-			}
-		}()
-
-		// timeout channel
-		for {
-			select {
-			case <-done:
-				return
-			case <-timeout.C:
-				once.Do(func() {
-					ready <- false
-					close(done)
-				})
-				return
-			}
-		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+		defer cancel()
+		probe := ResponseProbe(func(context.Context) (*http.Response, error) {
+			return readyCall()
+		}, cfg.readyResp)
+		ready <- WaitReady(ctx, probe,
+			retry.WithInitialBackoff(cfg.tickDuration),
+			retry.WithMultiplier(1),
+			retry.WithRandomizationFactor(0),
+		) == nil
 	}()
 	return ready
 }
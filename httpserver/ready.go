@@ -0,0 +1,278 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/palantir/pkg/retry"
+)
+
+// maxProbeBodyBytes is the maximum number of bytes of a probe response that are read.
+const maxProbeBodyBytes = 4096
+
+// Probe checks whether a server is ready. Returns nil if the server is ready, or an error that describes why it is not,
+// such as the status of the last response or the error returned when dialing the server.
+type Probe func(ctx context.Context) error
+
+// WaitReady calls probe until it returns nil or ctx is done. Probes are retried using the retry package with an
+// initial backoff of 100 milliseconds and a maximum backoff of 1 second, which can be overridden by the provided
+// options. A probe that does not return when ctx is done is abandoned.
+//
+// Returns nil once the server is ready. Otherwise, returns an error that includes the number of attempts and the error
+// returned by the last probe, or ctx.Err() if the probe was never called.
+func WaitReady(ctx context.Context, probe Probe, options ...retry.Option) error {
+	options = append([]retry.Option{
+		retry.WithInitialBackoff(100 * time.Millisecond),
+		retry.WithMaxBackoff(time.Second),
+	}, options...)
+
+	attempts := 0
+	var lastErr error
+	for r := retry.Start(ctx, options...); r.Next(); {
+		attempts++
+		lastErr = callProbe(ctx, probe)
+		if lastErr == nil {
+			return nil
+		}
+	}
+	if lastErr == nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("server was not ready after %d attempt(s): %w", attempts, lastErr)
+}
+
+// callProbe calls probe in a separate goroutine so that a probe that ignores its context does not block past the
+// deadline of ctx.
+func callProbe(ctx context.Context, probe Probe) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- probe(ctx)
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("probe did not complete: %w", ctx.Err())
+	}
+}
+
+// HTTPProbe returns a Probe that sends a GET request to the provided URL using the provided client, or
+// http.DefaultClient if client is nil. The server is ready if it responds with 200 OK.
+func HTTPProbe(client *http.Client, url string) Probe {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return ResponseProbe(func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return client.Do(req)
+	}, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusOK
+	})
+}
+
+// ResponseProbe returns a Probe that calls the provided function and reports the server as ready if readyResp returns
+// true for the returned response. The body of the response is closed by the probe. If the server is not ready, the
+// error includes the status and the beginning of the body of the response.
+func ResponseProbe(call func(ctx context.Context) (*http.Response, error), readyResp func(*http.Response) bool) Probe {
+	return func(ctx context.Context) error {
+		resp, err := call(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if readyResp(resp) {
+			return nil
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodyBytes))
+		if body := strings.TrimSpace(string(body)); body != "" {
+			return fmt.Errorf("server responded with %s: %s", resp.Status, body)
+		}
+		return fmt.Errorf("server responded with %s", resp.Status)
+	}
+}
+
+// TCPProbe returns a Probe that reports the server as ready once a TCP connection to the provided address can be
+// established.
+func TCPProbe(addr string) Probe {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// TLSProbe returns a Probe that reports the server as ready once a TLS handshake with the provided address completes
+// using the provided config, which determines the certificates that the server must present.
+func TLSProbe(addr string, tlsConfig *tls.Config) Probe {
+	return func(ctx context.Context) error {
+		dialer := tls.Dialer{Config: tlsConfig}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// gRPC health checking protocol, see https://github.com/grpc/grpc/blob/master/doc/health-checking.md.
+const (
+	grpcHealthCheckPath  = "/grpc.health.v1.Health/Check"
+	grpcStatusServing    = 1
+	grpcStatusHeader     = "Grpc-Status"
+	grpcMessageHeader    = "Grpc-Message"
+	grpcFrameHeaderBytes = 5
+)
+
+var grpcServingStatusNames = map[uint64]string{
+	0: "UNKNOWN",
+	1: "SERVING",
+	2: "NOT_SERVING",
+	3: "SERVICE_UNKNOWN",
+}
+
+// GRPCHealthProbe returns a Probe that calls the Check method of the standard gRPC health service
+// (grpc.health.v1.Health) of the server at baseURL, such as "https://localhost:8443", for the provided service name.
+// An empty service name checks the health of the server as a whole. The server is ready once it reports SERVING.
+//
+// gRPC requires HTTP/2. If client is nil, a client that uses HTTP/2 for both "https" and "http" URLs is used; a client
+// that is provided must do the same for the scheme of baseURL.
+func GRPCHealthProbe(client *http.Client, baseURL, service string) Probe {
+	if client == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+		client = &http.Client{Transport: transport}
+	}
+	url := strings.TrimSuffix(baseURL, "/") + grpcHealthCheckPath
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(grpcFrame(encodeHealthCheckRequest(service))))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/grpc")
+		req.Header.Set("TE", "trailers")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if resp.ProtoMajor != 2 {
+			return fmt.Errorf("gRPC requires HTTP/2 but server responded using %s", resp.Proto)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("server responded with %s", resp.Status)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodyBytes))
+		if err != nil {
+			return fmt.Errorf("failed to read gRPC response: %v", err)
+		}
+		// the status is sent in the trailers, or in the headers of responses without a body
+		status, message := resp.Trailer.Get(grpcStatusHeader), resp.Trailer.Get(grpcMessageHeader)
+		if status == "" {
+			status, message = resp.Header.Get(grpcStatusHeader), resp.Header.Get(grpcMessageHeader)
+		}
+		if status != "0" {
+			return fmt.Errorf("gRPC health check failed with status %q: %s", status, message)
+		}
+		servingStatus, err := decodeHealthCheckResponse(body)
+		if err != nil {
+			return err
+		}
+		if servingStatus != grpcStatusServing {
+			name, ok := grpcServingStatusNames[servingStatus]
+			if !ok {
+				name = fmt.Sprintf("%d", servingStatus)
+			}
+			return fmt.Errorf("gRPC health status is %s", name)
+		}
+		return nil
+	}
+}
+
+// encodeHealthCheckRequest returns the protobuf encoding of a HealthCheckRequest, which has a single string field
+// "service" with field number 1.
+func encodeHealthCheckRequest(service string) []byte {
+	if service == "" {
+		return nil
+	}
+	msg := []byte{0x0a} // field 1, wire type 2 (length-delimited)
+	msg = binary.AppendUvarint(msg, uint64(len(service)))
+	return append(msg, service...)
+}
+
+// decodeHealthCheckResponse returns the value of the "status" enum field (field number 1) of the HealthCheckResponse in
+// the provided gRPC response body. Unknown fields are skipped.
+func decodeHealthCheckResponse(body []byte) (uint64, error) {
+	if len(body) < grpcFrameHeaderBytes {
+		return 0, errors.New("gRPC response does not contain a message")
+	}
+	if body[0] != 0 {
+		return 0, errors.New("compressed gRPC responses are not supported")
+	}
+	length := binary.BigEndian.Uint32(body[1:grpcFrameHeaderBytes])
+	msg := body[grpcFrameHeaderBytes:]
+	if uint64(len(msg)) < uint64(length) {
+		return 0, errors.New("gRPC response message is truncated")
+	}
+	msg = msg[:length]
+
+	var status uint64
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return 0, errors.New("invalid gRPC health check response")
+		}
+		msg = msg[n:]
+		switch wireType := tag & 0x7; wireType {
+		case 0: // varint
+			value, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return 0, errors.New("invalid gRPC health check response")
+			}
+			msg = msg[n:]
+			if tag>>3 == 1 {
+				status = value
+			}
+		case 2: // length-delimited
+			length, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < length {
+				return 0, errors.New("invalid gRPC health check response")
+			}
+			msg = msg[n+int(length):]
+		default:
+			return 0, fmt.Errorf("unsupported protobuf wire type %d in gRPC health check response", wireType)
+		}
+	}
+	return status, nil
+}
+
+// grpcFrame returns msg prefixed with the gRPC length-prefixed message header for an uncompressed message.
+func grpcFrame(msg []byte) []byte {
+	frame := make([]byte, grpcFrameHeaderBytes, grpcFrameHeaderBytes+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
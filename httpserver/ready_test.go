@@ -0,0 +1,138 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpserver_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpserver"
+	"github.com/palantir/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitReadyHTTP(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(rw, "starting", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, httpserver.WaitReady(ctx, httpserver.HTTPProbe(nil, server.URL), retry.WithInitialBackoff(10*time.Millisecond)))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestWaitReadyDiagnostics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "database unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := httpserver.WaitReady(ctx, httpserver.HTTPProbe(nil, server.URL), retry.WithInitialBackoff(10*time.Millisecond), retry.WithMaxAttempts(2))
+	assert.EqualError(t, err, "server was not ready after 2 attempt(s): server responded with 503 Service Unavailable: database unavailable")
+
+	err = httpserver.WaitReady(ctx, httpserver.TCPProbe(closedAddr(t)), retry.WithMaxAttempts(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server was not ready after 1 attempt(s): dial tcp")
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+	err = httpserver.WaitReady(ctx, func(context.Context) error {
+		<-blocked
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "probes that ignore their context are abandoned")
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, httpserver.WaitReady(cancelledCtx, httpserver.TCPProbe(closedAddr(t))))
+}
+
+func TestTCPAndTLSProbes(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+	ctx := context.Background()
+
+	assert.NoError(t, httpserver.TCPProbe(addr)(ctx))
+	assert.NoError(t, httpserver.TLSProbe(addr, server.Client().Transport.(*http.Transport).TLSClientConfig)(ctx))
+	err := httpserver.TLSProbe(addr, &tls.Config{})(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate")
+}
+
+func TestGRPCHealthProbe(t *testing.T) {
+	statuses := map[string]uint64{"": 1, "database": 2}
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/grpc.health.v1.Health/Check" || req.Header.Get("Content-Type") != "application/grpc" {
+			http.NotFound(rw, req)
+			return
+		}
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		var service string
+		if len(body) > 5 {
+			// field 1 with a single byte length
+			service = string(body[7:])
+		}
+		rw.Header().Set("Content-Type", "application/grpc")
+		status, ok := statuses[service]
+		if !ok {
+			rw.Header().Set("Grpc-Status", "5")
+			rw.Header().Set("Grpc-Message", "unknown service")
+			return
+		}
+		rw.Header().Set("Trailer", "Grpc-Status")
+		msg := binary.AppendUvarint([]byte{0x08}, status)
+		frame := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(msg)))
+		_, _ = rw.Write(append(frame, msg...))
+		rw.Header().Set("Grpc-Status", "0")
+	})
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	plaintextServer := httptest.NewUnstartedServer(handler)
+	plaintextServer.Config.Protocols = new(http.Protocols)
+	plaintextServer.Config.Protocols.SetUnencryptedHTTP2(true)
+	plaintextServer.Start()
+	defer plaintextServer.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, httpserver.GRPCHealthProbe(tlsServer.Client(), tlsServer.URL, "")(ctx))
+	assert.NoError(t, httpserver.GRPCHealthProbe(nil, plaintextServer.URL, "")(ctx))
+	assert.EqualError(t, httpserver.GRPCHealthProbe(nil, plaintextServer.URL, "database")(ctx), "gRPC health status is NOT_SERVING")
+	assert.EqualError(t, httpserver.GRPCHealthProbe(nil, plaintextServer.URL, "unknown")(ctx), `gRPC health check failed with status "5": unknown service`)
+
+	http1Server := httptest.NewServer(handler)
+	defer http1Server.Close()
+	assert.EqualError(t, httpserver.GRPCHealthProbe(http.DefaultClient, http1Server.URL, "")(ctx), "gRPC requires HTTP/2 but server responded using HTTP/1.1")
+}
+
+// closedAddr returns the address of a listener that has been closed.
+func closedAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Server is an HTTP server started by Run.
+type Server struct {
+	addr           string
+	managementAddr string
+	draining       atomic.Bool
+	done           chan struct{}
+	err            error
+}
+
+// Run starts an HTTP server that serves the provided handler and returns once its listeners are bound, so the
+// addresses of the server are available as soon as Run returns. By default the server listens on ":0", which binds a
+// random available port; use AddressParam to configure the address.
+//
+// The server shuts down gracefully when ctx is cancelled or when the process receives SIGTERM or SIGINT, unless
+// DisableSignalHandlingParam is provided. The signals are only handled until the server has shut down. On shutdown,
+// the server first marks itself as draining and continues to serve requests for the drain period configured using
+// DrainPeriodParam, which gives load balancers time to stop routing requests to it. It then stops accepting
+// connections and waits for in-flight requests to complete for up to the shutdown timeout configured using
+// ShutdownTimeoutParam, after which remaining connections are closed. If a management server is configured using
+// ManagementParam, it is shut down after the main server so that health and metrics endpoints remain available while
+// the main server drains.
+//
+// Use Wait or Done to determine when shutdown has completed.
+func Run(ctx context.Context, handler http.Handler, params ...RunParam) (*Server, error) {
+	cfg := &runConfig{
+		addr:            ":0",
+		shutdownTimeout: defaultShutdownTimeout,
+	}
+	for _, p := range params {
+		if p != nil {
+			p.configRun(cfg)
+		}
+	}
+
+	mainServer := cfg.newHTTPServer(handler, cfg.tlsConfig)
+	mainListener := cfg.listener
+	if mainListener == nil {
+		var err error
+		if mainListener, err = net.Listen("tcp", cfg.addr); err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %v", cfg.addr, err)
+		}
+	}
+	var managementServer *http.Server
+	var managementListener net.Listener
+	if cfg.managementHandler != nil {
+		managementServer = cfg.newHTTPServer(cfg.managementHandler, cfg.managementTLSConfig)
+		var err error
+		managementListener, err = net.Listen("tcp", cfg.managementAddr)
+		if err != nil {
+			_ = mainListener.Close()
+			return nil, fmt.Errorf("failed to listen on management address %s: %v", cfg.managementAddr, err)
+		}
+	}
+
+	s := &Server{
+		addr: mainListener.Addr().String(),
+		done: make(chan struct{}),
+	}
+	serveErrs := make(chan error, 2)
+	serve(mainServer, mainListener, serveErrs)
+	if managementServer != nil {
+		s.managementAddr = managementListener.Addr().String()
+		serve(managementServer, managementListener, serveErrs)
+	}
+
+	shutdownCtx, stop := ctx, context.CancelFunc(func() {})
+	if !cfg.disableSignalHandling {
+		shutdownCtx, stop = signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	}
+	go func() {
+		defer close(s.done)
+		defer stop()
+
+		var serveErr error
+		select {
+		case <-shutdownCtx.Done():
+		case serveErr = <-serveErrs:
+		}
+		s.draining.Store(true)
+		if serveErr == nil && cfg.drainPeriod > 0 {
+			time.Sleep(cfg.drainPeriod)
+		}
+		s.err = errors.Join(serveErr, shutdown(mainServer, s.addr, cfg.shutdownTimeout))
+		if managementServer != nil {
+			s.err = errors.Join(s.err, shutdown(managementServer, s.managementAddr, cfg.shutdownTimeout))
+		}
+	}()
+	return s, nil
+}
+
+func serve(server *http.Server, listener net.Listener, errs chan<- error) {
+	go func() {
+		var err error
+		if server.TLSConfig != nil {
+			// certificates are provided by the TLS config
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs <- fmt.Errorf("server on %s failed: %v", listener.Addr(), err)
+		}
+	}()
+}
+
+// shutdown gracefully shuts down the server, closing all remaining connections if in-flight requests do not complete
+// within the timeout.
+func shutdown(server *http.Server, addr string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		_ = server.Close()
+		return fmt.Errorf("failed to gracefully shut down server on %s: %v", addr, err)
+	}
+	return nil
+}
+
+// Addr returns the address that the server is listening on, such as "127.0.0.1:54321".
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// ManagementAddr returns the address that the management server is listening on, or an empty string if no management
+// server was configured.
+func (s *Server) ManagementAddr() string {
+	return s.managementAddr
+}
+
+// Draining returns true once shutdown has started. Handlers such as readiness checks can use this to report that the
+// server should no longer receive traffic.
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// Done returns a channel that is closed once the server has shut down.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait blocks until the server has shut down. Returns an error if a listener failed or if in-flight requests did not
+// complete within the shutdown timeout.
+func (s *Server) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// RunParam configures Run.
+type RunParam interface {
+	configRun(*runConfig)
+}
+
+type runParam func(*runConfig)
+
+func (p runParam) configRun(cfg *runConfig) {
+	p(cfg)
+}
+
+// AddressParam configures the address that the server listens on. The default is ":0".
+func AddressParam(addr string) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.addr = addr
+	})
+}
+
+// ListenerParam configures the server to serve on the provided listener, which is already bound, instead of listening
+// on the address configured using AddressParam. The listener is closed when the server shuts down or if Run returns an
+// error.
+func ListenerParam(listener net.Listener) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.listener = listener
+	})
+}
+
+// TLSConfigParam configures the server to serve TLS using the provided config, such as one returned by
+// tlsconfig.NewServerConfig. The config must provide the server certificate.
+func TLSConfigParam(tlsConfig *tls.Config) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.tlsConfig = tlsConfig
+	})
+}
+
+// ManagementParam configures a separate server that listens on the provided address and serves the provided handler,
+// which typically serves health and metrics endpoints. The management server uses the TLS config provided by
+// ManagementTLSConfigParam, and plain HTTP if none is provided.
+func ManagementParam(addr string, handler http.Handler) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.managementAddr = addr
+		cfg.managementHandler = handler
+	})
+}
+
+// ManagementTLSConfigParam configures the management server to serve TLS using the provided config.
+func ManagementTLSConfigParam(tlsConfig *tls.Config) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.managementTLSConfig = tlsConfig
+	})
+}
+
+// DrainPeriodParam configures the period for which the server continues to serve requests after shutdown has started.
+// The default is 0.
+func DrainPeriodParam(d time.Duration) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.drainPeriod = d
+	})
+}
+
+// ShutdownTimeoutParam configures how long shutdown waits for in-flight requests to complete before closing their
+// connections. The default is 30 seconds.
+func ShutdownTimeoutParam(d time.Duration) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.shutdownTimeout = d
+	})
+}
+
+// DisableSignalHandlingParam configures the server not to shut down when the process receives SIGTERM or SIGINT, so
+// that the signals keep their default behavior and the server only shuts down when the context provided to Run is
+// cancelled. Servers started by tests should use it so that interrupting the test process still terminates it.
+func DisableSignalHandlingParam() RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.disableSignalHandling = true
+	})
+}
+
+// HTTPServerParam configures a function that is called with each http.Server created by Run before it starts serving,
+// which can be used to configure timeouts or other settings. The Handler and TLSConfig fields should not be modified.
+func HTTPServerParam(configure func(*http.Server)) RunParam {
+	return runParam(func(cfg *runConfig) {
+		cfg.configureServer = configure
+	})
+}
+
+type runConfig struct {
+	addr                  string
+	listener              net.Listener
+	tlsConfig             *tls.Config
+	managementAddr        string
+	managementHandler     http.Handler
+	managementTLSConfig   *tls.Config
+	drainPeriod           time.Duration
+	shutdownTimeout       time.Duration
+	disableSignalHandling bool
+	configureServer       func(*http.Server)
+}
+
+func (cfg *runConfig) newHTTPServer(handler http.Handler, tlsConfig *tls.Config) *http.Server {
+	server := &http.Server{
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if cfg.configureServer != nil {
+		cfg.configureServer(server)
+	}
+	return server
+}
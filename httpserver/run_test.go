@@ -0,0 +1,160 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpserver_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunGracefulShutdown(t *testing.T) {
+	requestStarted := make(chan struct{})
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			close(requestStarted)
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = io.WriteString(rw, "main")
+	})
+	management := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(rw, "management")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, err := httpserver.Run(ctx, handler,
+		httpserver.AddressParam("127.0.0.1:0"),
+		httpserver.ManagementParam("127.0.0.1:0", management),
+		httpserver.DrainPeriodParam(100*time.Millisecond),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "main", get(t, http.DefaultClient, "http://"+server.Addr()))
+	assert.Equal(t, "management", get(t, http.DefaultClient, "http://"+server.ManagementAddr()))
+	assert.False(t, server.Draining())
+
+	slowResponse := make(chan string)
+	go func() {
+		slowResponse <- get(t, http.DefaultClient, "http://"+server.Addr()+"/slow")
+	}()
+	<-requestStarted
+	cancel()
+
+	// requests are served during the drain period
+	assert.Eventually(t, server.Draining, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "main", get(t, http.DefaultClient, "http://"+server.Addr()))
+
+	require.NoError(t, server.Wait())
+	assert.Equal(t, "main", <-slowResponse, "in-flight requests complete before shutdown")
+	_, err = http.Get("http://" + server.Addr())
+	assert.Error(t, err)
+	_, err = http.Get("http://" + server.ManagementAddr())
+	assert.Error(t, err)
+}
+
+func TestRunShutdownTimeout(t *testing.T) {
+	requestStarted := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		close(requestStarted)
+		<-release
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server, err := httpserver.Run(ctx, handler, httpserver.ShutdownTimeoutParam(50*time.Millisecond))
+	require.NoError(t, err)
+	go func() {
+		_, _ = http.Get("http://" + server.Addr())
+	}()
+	<-requestStarted
+	cancel()
+
+	err = server.Wait()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to gracefully shut down server on "+server.Addr())
+}
+
+func TestRunTLS(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(nil)
+	tlsConfig, client := tlsServer.TLS.Clone(), tlsServer.Client()
+	tlsServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server, err := httpserver.Run(ctx, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(rw, req.Proto)
+	}), httpserver.AddressParam("127.0.0.1:0"), httpserver.TLSConfigParam(tlsConfig))
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1", get(t, client, "https://"+server.Addr()))
+	cancel()
+	assert.NoError(t, server.Wait())
+}
+
+func TestRunAddressInUse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, err := httpserver.Run(ctx, http.NotFoundHandler(), httpserver.AddressParam("127.0.0.1:0"))
+	require.NoError(t, err)
+
+	_, err = httpserver.Run(ctx, http.NotFoundHandler(), httpserver.AddressParam(server.Addr()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to listen on "+server.Addr())
+
+	_, err = httpserver.Run(ctx, http.NotFoundHandler(), httpserver.ManagementParam(server.Addr(), http.NotFoundHandler()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to listen on management address "+server.Addr())
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestRunSignalHandling(t *testing.T) {
+	// keep the signal from terminating the test process
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, syscall.SIGTERM)
+	defer signal.Stop(received)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ignoring, err := httpserver.Run(ctx, http.NotFoundHandler(), httpserver.AddressParam("127.0.0.1:0"), httpserver.DisableSignalHandlingParam())
+	require.NoError(t, err)
+	handling, err := httpserver.Run(context.Background(), http.NotFoundHandler(), httpserver.AddressParam("127.0.0.1:0"))
+	require.NoError(t, err)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+	<-received
+	select {
+	case <-handling.Done():
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "server did not shut down after SIGTERM")
+	}
+	require.NoError(t, handling.Wait())
+	select {
+	case <-ignoring.Done():
+		assert.Fail(t, "server with signal handling disabled shut down after SIGTERM")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, ignoring.Wait())
+}
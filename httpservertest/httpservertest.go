@@ -0,0 +1,184 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpservertest starts HTTP servers for use in tests. Servers listen on listeners that are bound before they
+// are handed out, so parallel tests never race for the same port, can serve plain HTTP, TLS or mutual TLS using
+// generated certificates, and are shut down when the test completes.
+package httpservertest
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/httpclient"
+	"github.com/palantir/pkg/httpserver"
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/palantir/pkg/tlsconfig/tlsconfigtest"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Listen returns a TCP listener that is bound to a random available port on 127.0.0.1. Unlike
+// httpserver.AvailablePort, the port remains reserved until the listener is closed. The listener is closed when the
+// test completes if it has not been closed already. Calls t.Fatal if the listener cannot be created.
+func Listen(t testing.TB) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	return listener
+}
+
+// Param configures a server started by Start.
+type Param func(*config)
+
+type config struct {
+	tls         bool
+	mutualTLS   bool
+	runParams   []httpserver.RunParam
+	certOptions []tlsconfigtest.CertOption
+}
+
+// TLS configures the server to serve TLS using a server certificate for "localhost", 127.0.0.1 and ::1 issued by a
+// generated CA. The provided options are applied to the server certificate.
+func TLS(opts ...tlsconfigtest.CertOption) Param {
+	return func(c *config) {
+		c.tls = true
+		c.certOptions = append(c.certOptions, opts...)
+	}
+}
+
+// MutualTLS configures the server to serve TLS like TLS and to require clients to present a certificate issued by the
+// generated CA. Server.Client uses a generated client certificate.
+func MutualTLS(opts ...tlsconfigtest.CertOption) Param {
+	return func(c *config) {
+		TLS(opts...)(c)
+		c.mutualTLS = true
+	}
+}
+
+// RunParams configures additional parameters provided to httpserver.Run, such as httpserver.ManagementParam.
+func RunParams(params ...httpserver.RunParam) Param {
+	return func(c *config) {
+		c.runParams = append(c.runParams, params...)
+	}
+}
+
+// Server is a server started by Start.
+type Server struct {
+	*httpserver.Server
+
+	// URL is the base URL of the server, such as "https://127.0.0.1:54321".
+	URL string
+	// CA is the CA that issued the server certificate and the client certificate, or nil if the server does not use TLS.
+	CA *tlsconfigtest.CA
+	// ServerCert is the certificate presented by the server, or nil if the server does not use TLS.
+	ServerCert *tlsconfigtest.Cert
+	// ClientCert is the certificate presented by clients returned by Client, or nil if the server does not use mutual
+	// TLS.
+	ClientCert *tlsconfigtest.Cert
+}
+
+// Start starts a server that serves the provided handler on a listener returned by Listen using httpserver.Run with
+// signal handling disabled. The server is shut down when the test completes, and the test fails if the server does
+// not shut down cleanly. Calls t.Fatal if the server cannot be started.
+func Start(t testing.TB, handler http.Handler, params ...Param) *Server {
+	t.Helper()
+	cfg := &config{}
+	for _, p := range params {
+		if p != nil {
+			p(cfg)
+		}
+	}
+
+	s := &Server{}
+	runParams := []httpserver.RunParam{
+		httpserver.ListenerParam(Listen(t)),
+		httpserver.ShutdownTimeoutParam(shutdownTimeout),
+		// interrupting the test process should terminate it rather than shut down the server
+		httpserver.DisableSignalHandlingParam(),
+	}
+	scheme := "http"
+	if cfg.tls {
+		scheme = "https"
+		s.CA = tlsconfigtest.NewCA(t)
+		s.ServerCert = s.CA.NewServerCert(t, cfg.certOptions...)
+		serverParams := []tlsconfig.ServerParam{tlsconfig.ServerNextProtos("h2", "http/1.1")}
+		if cfg.mutualTLS {
+			s.ClientCert = s.CA.NewClientCert(t)
+			serverParams = append(serverParams,
+				tlsconfig.ServerClientCAs(s.CA.CertPoolProvider()),
+				tlsconfig.ServerClientAuthType(tls.RequireAndVerifyClientCert),
+			)
+		}
+		tlsConfig, err := tlsconfig.NewServerConfig(s.ServerCert.TLSCertProvider(), serverParams...)
+		if err != nil {
+			t.Fatalf("failed to create server TLS config: %v", err)
+		}
+		runParams = append(runParams, httpserver.TLSConfigParam(tlsConfig))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server, err := httpserver.Run(ctx, handler, append(runParams, cfg.runParams...)...)
+	if err != nil {
+		cancel()
+		t.Fatalf("failed to start server: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		if err := server.Wait(); err != nil {
+			t.Errorf("server did not shut down cleanly: %v", err)
+		}
+	})
+	s.Server = server
+	s.URL = scheme + "://" + server.Addr()
+	return s
+}
+
+// TLSClientConfig returns a TLS config that trusts the CA of the server and presents the client certificate if the
+// server uses mutual TLS. Returns nil if the server does not use TLS. Calls t.Fatal if the config cannot be created.
+func (s *Server) TLSClientConfig(t testing.TB) *tls.Config {
+	t.Helper()
+	if s.CA == nil {
+		return nil
+	}
+	tlsConfig, err := tlsconfig.NewClientConfig(s.tlsClientParams()...)
+	if err != nil {
+		t.Fatalf("failed to create client TLS config: %v", err)
+	}
+	return tlsConfig
+}
+
+// Client returns a client created by httpclient.NewClient that trusts the CA of the server and presents the client
+// certificate if the server uses mutual TLS. The provided parameters are applied after the TLS parameters. Idle
+// connections are closed when the test completes. Calls t.Fatal if the client cannot be created.
+func (s *Server) Client(t testing.TB, params ...httpclient.ClientParam) *http.Client {
+	t.Helper()
+	var clientParams []httpclient.ClientParam
+	if s.CA != nil {
+		clientParams = append(clientParams, httpclient.WithTLSParams(s.tlsClientParams()...))
+	}
+	client, err := httpclient.NewClient(append(clientParams, params...)...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(client.CloseIdleConnections)
+	return client
+}
+
+func (s *Server) tlsClientParams() []tlsconfig.ClientParam {
+	params := []tlsconfig.ClientParam{tlsconfig.ClientRootCAs(s.CA.CertPoolProvider())}
+	if s.ClientCert != nil {
+		params = append(params, tlsconfig.ClientKeyPair(s.ClientCert.TLSCertProvider()))
+	}
+	return params
+}
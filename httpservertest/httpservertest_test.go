@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpservertest_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/palantir/pkg/httpclient"
+	"github.com/palantir/pkg/httpserver"
+	"github.com/palantir/pkg/httpservertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	clientCN := "none"
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		clientCN = req.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	_, _ = io.WriteString(rw, req.Proto+" "+clientCN)
+})
+
+func TestStart(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		params     []httpservertest.Param
+		wantScheme string
+		wantBody   string
+	}{
+		{name: "HTTP", wantScheme: "http://", wantBody: "HTTP/1.1 none"},
+		{name: "TLS", params: []httpservertest.Param{httpservertest.TLS()}, wantScheme: "https://", wantBody: "HTTP/1.1 none"},
+		{name: "mutual TLS", params: []httpservertest.Param{httpservertest.MutualTLS()}, wantScheme: "https://", wantBody: "HTTP/1.1 client"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			server := httpservertest.Start(t, handler, tc.params...)
+			assert.True(t, strings.HasPrefix(server.URL, tc.wantScheme), server.URL)
+			assert.Equal(t, tc.wantBody, get(t, server.Client(t), server.URL))
+		})
+	}
+}
+
+func TestMutualTLSRequiresClientCert(t *testing.T) {
+	server := httpservertest.Start(t, handler, httpservertest.MutualTLS())
+	assert.Equal(t, "HTTP/2.0 client", get(t, server.Client(t, httpclient.WithHTTP2()), server.URL))
+
+	tlsConfig := server.TLSClientConfig(t)
+	tlsConfig.Certificates = nil
+	tlsConfig.GetClientCertificate = nil
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+}
+
+func TestStartWithRunParams(t *testing.T) {
+	health := httpserver.NewHealthRegistry()
+	server := httpservertest.Start(t, handler, httpservertest.RunParams(
+		httpserver.ManagementParam("127.0.0.1:0", health.Handler(httpserver.Readiness)),
+	))
+	assert.Equal(t, `{"status":"HEALTHY","checks":{}}`+"\n", get(t, server.Client(t), "http://"+server.ManagementAddr()))
+}
+
+func TestListen(t *testing.T) {
+	listener := httpservertest.Listen(t)
+	other := httpservertest.Listen(t)
+	assert.NotEqual(t, listener.Addr().String(), other.Addr().String())
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
@@ -183,3 +183,43 @@ func BenchmarkEach(b *testing.B) {
 		})
 	})
 }
+
+func BenchmarkGetExistingMetric(b *testing.B) {
+	for _, n := range []int{1, 10} {
+		var tags Tags
+		for i := 0; i < n; i++ {
+			tags = append(tags, MustNewTag(fmt.Sprintf("key%d", i), fmt.Sprintf("val%d", i)))
+		}
+		root := NewRootMetricsRegistry()
+		b.Run(fmt.Sprintf("%d tags/Tags", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				root.Counter("metricName", tags...).Inc(1)
+			}
+		})
+		b.Run(fmt.Sprintf("%d tags/TagSet", n), func(b *testing.B) {
+			reg := WithTagSet(root, NewTagSet(tags...))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				reg.Counter("metricName").Inc(1)
+			}
+		})
+		b.Run(fmt.Sprintf("%d tags/AddTags", n), func(b *testing.B) {
+			ctx := AddTags(WithRegistry(context.Background(), root), tags...)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				FromContext(ctx).Counter("metricName").Inc(1)
+			}
+		})
+		b.Run(fmt.Sprintf("%d tags/AddTagSet", n), func(b *testing.B) {
+			ctx := AddTagSet(WithRegistry(context.Background(), root), NewTagSet(tags...))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				FromContext(ctx).Counter("metricName").Inc(1)
+			}
+		})
+	}
+}
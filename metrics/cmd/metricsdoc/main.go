@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command metricsdoc generates Markdown documentation for the metrics declared in one or more metric schema YAML files.
+// See metrics.LoadSchema for the format of the schema files.
+//
+// With -lint, metricsdoc instead checks the schema files for the problems reported by metrics.Schema.Lint, prints them
+// and exits with a non-zero status if there are any.
+//
+// Usage:
+//
+//	metricsdoc [-o output.md] schema.yml [schema.yml...]
+//	metricsdoc -lint schema.yml [schema.yml...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/pkg/errors"
+)
+
+func main() {
+	outputFlag := flag.String("o", "", "file to which documentation is written (defaults to stdout)")
+	lintFlag := flag.Bool("lint", false, "check the schema files for problems instead of generating documentation")
+	flag.Parse()
+	if err := run(flag.Args(), *outputFlag, *lintFlag, os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(schemaPaths []string, outputPath string, lint bool, stdout io.Writer) error {
+	if len(schemaPaths) == 0 {
+		return errors.New("at least one schema file must be provided")
+	}
+	var definitions []metrics.MetricDefinition
+	for _, path := range schemaPaths {
+		schemaBytes, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read schema file %s", path)
+		}
+		schema, err := metrics.LoadSchema(schemaBytes)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load schema file %s", path)
+		}
+		definitions = append(definitions, schema.Definitions()...)
+	}
+	schema, err := metrics.NewSchema(definitions...)
+	if err != nil {
+		return err
+	}
+	if lint {
+		problems := schema.Lint()
+		for _, problem := range problems {
+			_, _ = fmt.Fprintln(stdout, problem)
+		}
+		if len(problems) > 0 {
+			return errors.Errorf("found %d problem(s) in metric schema", len(problems))
+		}
+		return nil
+	}
+
+	out := stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create output file %s", outputPath)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		out = f
+	}
+	return schema.WriteMarkdown(out)
+}
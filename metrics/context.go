@@ -18,6 +18,9 @@ var DefaultMetricsRegistry = NewRootMetricsRegistry()
 
 func WithRegistry(ctx context.Context, registry Registry) context.Context {
 	if container, ok := ctx.Value(registryKey).(*registryContainer); ok {
+		if container.tagSetRegistry != nil {
+			return context.WithValue(ctx, registryKey, newRegistryContainerWithTagSet(registry, container.Tags, container.tagSetRegistry.tags))
+		}
 		return context.WithValue(ctx, registryKey, &registryContainer{
 			Registry: registry,
 			Tags:     container.Tags,
@@ -33,6 +36,9 @@ func FromContext(ctx context.Context) Registry {
 	if !ok {
 		return DefaultMetricsRegistry
 	}
+	if prev.tagSetRegistry != nil {
+		return prev.tagSetRegistry
+	}
 	registry, ok := prev.Registry.(*rootRegistry)
 	if !ok {
 		return prev.Registry
@@ -81,4 +87,7 @@ func TagsFromContext(ctx context.Context) Tags {
 type registryContainer struct {
 	Registry Registry
 	Tags     Tags
+
+	// tagSetRegistry is set by AddTagSet if Registry is a root registry and is returned by FromContext.
+	tagSetRegistry *tagSetRegistry
 }
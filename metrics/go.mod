@@ -8,10 +8,10 @@ require (
 	github.com/palantir/pkg/objmatcher v1.2.0
 	github.com/pkg/errors v0.8.1
 	github.com/stretchr/testify v1.11.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,179 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpmetrics provides net/http middleware that records standard server request metrics.
+package httpmetrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+)
+
+const (
+	// ResponseTimerName is the name of the timer that records the time taken to serve each request.
+	ResponseTimerName = "server.response"
+	// ResponseStatusMeterName is the name of the meter that is marked for every response. It is tagged with the
+	// status family of the response ("1xx", "2xx", "3xx", "4xx" or "5xx") using the key StatusFamilyTagKey.
+	ResponseStatusMeterName = "server.response.status"
+	// ResponseSizeHistogramName is the name of the histogram that records the number of bytes in each response body.
+	ResponseSizeHistogramName = "server.response.size"
+	// InFlightCounterName is the name of the counter whose count is the number of requests currently being served.
+	InFlightCounterName = "server.request.inflight"
+
+	MethodTagKey       = "method"
+	RouteTagKey        = "route"
+	StatusFamilyTagKey = "family"
+
+	// OtherMethodTagValue is the value of the method tag for requests whose method is not one of the standard methods
+	// defined by RFC 9110 and RFC 5789, so that clients cannot create an unbounded number of tag values.
+	OtherMethodTagValue = "other"
+)
+
+// Param configures the middleware returned by NewMiddleware.
+type Param func(*middleware)
+
+// RouteTemplate sets a function that returns the route template that matched a request (for example,
+// "/users/{userId}"). The template is recorded using the tag key RouteTagKey. The function should return a value with
+// bounded cardinality: it should not return the request path. If the function returns the empty string, the route tag
+// is not added.
+func RouteTemplate(route func(req *http.Request) string) Param {
+	return func(m *middleware) {
+		m.route = route
+	}
+}
+
+// Tags sets a function that returns additional tags to add to the metrics recorded for a request.
+func Tags(tags func(req *http.Request) metrics.Tags) Param {
+	return func(m *middleware) {
+		m.tags = tags
+	}
+}
+
+// DisableMethodTag configures the middleware not to tag metrics with the request method.
+func DisableMethodTag() Param {
+	return func(m *middleware) {
+		m.disableMethodTag = true
+	}
+}
+
+// NewMiddleware returns middleware that records request latency, response status families, response sizes and the
+// number of in-flight requests on the provided registry.
+//
+// Before invoking the wrapped handler, the middleware installs the registry in the request context using
+// metrics.WithRegistry and adds the method, route and custom tags using metrics.AddTags, so metrics.FromContext
+// returns a registry that tags the metrics that handlers record in the same manner as the request metrics. The registry
+// must be a root registry because metrics.FromContext only applies context tags to root registries.
+func NewMiddleware(registry metrics.RootRegistry, params ...Param) func(http.Handler) http.Handler {
+	m := &middleware{
+		registry: registry,
+	}
+	for _, param := range params {
+		if param != nil {
+			param(m)
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			m.serveHTTP(next, rw, req)
+		})
+	}
+}
+
+type middleware struct {
+	registry         metrics.RootRegistry
+	route            func(req *http.Request) string
+	tags             func(req *http.Request) metrics.Tags
+	disableMethodTag bool
+}
+
+func (m *middleware) serveHTTP(next http.Handler, rw http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+
+	ctx := metrics.WithRegistry(req.Context(), m.registry)
+	ctx = metrics.AddTags(ctx, m.requestTags(req)...)
+	registry := metrics.FromContext(ctx)
+
+	inFlight := registry.Counter(InFlightCounterName)
+	inFlight.Inc(1)
+	defer inFlight.Dec(1)
+
+	recorder := &responseRecorder{ResponseWriter: rw, status: http.StatusOK}
+	next.ServeHTTP(recorder, req.WithContext(ctx))
+
+	registry.Timer(ResponseTimerName).UpdateSince(start)
+	registry.Meter(ResponseStatusMeterName, metrics.MustNewTag(StatusFamilyTagKey, statusFamily(recorder.status))).Mark(1)
+	registry.Histogram(ResponseSizeHistogramName).Update(recorder.size)
+}
+
+func (m *middleware) requestTags(req *http.Request) metrics.Tags {
+	var tags metrics.Tags
+	if !m.disableMethodTag {
+		tags = append(tags, metrics.MustNewTag(MethodTagKey, methodTagValue(req.Method)))
+	}
+	if m.route != nil {
+		if route := m.route(req); route != "" {
+			tags = append(tags, metrics.NewTagWithFallbackValue(RouteTagKey, route, "unknown"))
+		}
+	}
+	if m.tags != nil {
+		tags = append(tags, m.tags(req)...)
+	}
+	return tags
+}
+
+// methodTagValue returns the method if it is a standard method, or OtherMethodTagValue otherwise.
+func methodTagValue(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+		http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	default:
+		return OtherMethodTagValue
+	}
+}
+
+func statusFamily(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// responseRecorder records the status code and number of body bytes written to a http.ResponseWriter.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	// informational responses are followed by the final response
+	if !r.wroteHeader && status >= http.StatusOK {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher for handlers that stream responses.
+func (r *responseRecorder) Flush() {
+	r.wroteHeader = true
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter so that it can be used by http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
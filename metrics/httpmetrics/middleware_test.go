@@ -0,0 +1,102 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpmetrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/metrics/httpmetrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	var inFlightDuringRequest int64
+	handler := httpmetrics.NewMiddleware(root,
+		httpmetrics.RouteTemplate(func(req *http.Request) string {
+			return "/users/{userId}"
+		}),
+		httpmetrics.Tags(func(req *http.Request) metrics.Tags {
+			return metrics.Tags{metrics.MustNewTag("service", "users")}
+		}),
+	)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		reg := metrics.FromContext(req.Context())
+		inFlightDuringRequest = reg.Counter(httpmetrics.InFlightCounterName).Count()
+		reg.Counter("handler.counter").Inc(1)
+		if req.URL.Query().Get("fail") != "" {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = rw.Write([]byte("hello"))
+	}))
+
+	for _, target := range []string{"/users/1", "/users/2", "/users/3?fail=true"} {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, "hello", rw.Body.String())
+	}
+	assert.Equal(t, int64(1), inFlightDuringRequest)
+
+	requestTags := metrics.Tags{
+		metrics.MustNewTag("method", "get"),
+		metrics.MustNewTag("route", "/users/_userid_"),
+		metrics.MustNewTag("service", "users"),
+	}
+	assert.Equal(t, int64(0), root.Counter(httpmetrics.InFlightCounterName, requestTags...).Count())
+	assert.Equal(t, int64(3), root.Timer(httpmetrics.ResponseTimerName, requestTags...).Count())
+	assert.Equal(t, int64(15), root.Histogram(httpmetrics.ResponseSizeHistogramName, requestTags...).Sum())
+	assert.Equal(t, int64(2), root.Meter(httpmetrics.ResponseStatusMeterName, append(requestTags, metrics.MustNewTag("family", "2xx"))...).Count())
+	assert.Equal(t, int64(1), root.Meter(httpmetrics.ResponseStatusMeterName, append(requestTags, metrics.MustNewTag("family", "5xx"))...).Count())
+	// metrics recorded by the handler using the registry in the request context have the request tags
+	assert.Equal(t, int64(3), root.Counter("handler.counter", requestTags...).Count())
+}
+
+func TestMiddleware_NonStandardMethod(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	handler := httpmetrics.NewMiddleware(root)(http.NotFoundHandler())
+	for _, method := range []string{"PROPFIND", "X-RANDOM-1", "get"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/", nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPatch, "/", nil))
+
+	assert.Equal(t, int64(3), root.Timer("server.response", metrics.MustNewTag("method", "other")).Count())
+	assert.Equal(t, int64(1), root.Timer("server.response", metrics.MustNewTag("method", "patch")).Count())
+}
+
+func TestMiddleware_DisableMethodTag(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	handler := httpmetrics.NewMiddleware(root, httpmetrics.DisableMethodTag())(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	var got []string
+	root.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		for _, tag := range tags {
+			name += "|" + tag.String()
+		}
+		got = append(got, name)
+	})
+	assert.Equal(t, []string{
+		"server.request.inflight",
+		"server.response",
+		"server.response.size",
+		"server.response.status|family:4xx",
+	}, got)
+}
+
+func TestMiddleware_Flush(t *testing.T) {
+	handler := httpmetrics.NewMiddleware(metrics.NewRootMetricsRegistry())(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		flusher, ok := rw.(http.Flusher)
+		require.True(t, ok)
+		flusher.Flush()
+	}))
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, rw.Flushed)
+}
@@ -98,12 +98,13 @@ var runtimeMemStats sync.Once
 // a goroutine which collects them every collectionFreq. This function can only be called once per lifetime of the
 // process and only records metrics if the provided RootRegistry is a *rootRegistry.
 //
-// Deprecated: use CaptureRuntimeMemStatsWithContext instead. CaptureRuntimeMemStatsWithContext has the following
+// Deprecated: use CaptureRuntimeMetricsWithContext instead. CaptureRuntimeMetricsWithContext has the following
 // advantages over this function:
 //   - Does not make assumptions about the concrete struct implementing of RootRegistry
 //   - Does not restrict the function to being called only once globally
 //   - Supports cancellation using a provided context
-//   - Can tell if provided RootRegistry does not support Go runtime metric collection based on return value
+//   - Reports an error if the requested runtime metrics are not supported
+//   - Does not stop the world to collect memory statistics
 func CaptureRuntimeMemStats(registry RootRegistry, collectionFreq time.Duration) {
 	runtimeMemStats.Do(func() {
 		if reg, ok := registry.(*rootRegistry); ok {
@@ -124,6 +125,9 @@ func CaptureRuntimeMemStats(registry RootRegistry, collectionFreq time.Duration)
 // The gauges/metrics etc. used to track runtime statistics are shared globally and the values are reset every time this
 // function is called (if it is not a no-op). Note that this function should typically only be called once per Go
 // runtime, but no enforcement of this is performed.
+//
+// Deprecated: use CaptureRuntimeMetricsWithContext instead, which reads the runtime/metrics package and does not stop
+// the world to collect memory statistics.
 func CaptureRuntimeMemStatsWithContext(ctx context.Context, registry RootRegistry, collectionFreq time.Duration) bool {
 	mRegProvider, ok := registry.(metricsRegistryProvider)
 	if !ok {
@@ -154,6 +158,9 @@ func CaptureRuntimeMemStatsWithContext(ctx context.Context, registry RootRegistr
 //
 // The gauges/metrics etc. used to track runtime statistics are shared globally and the values are reset every time this
 // function is called (if it is not a no-op).
+//
+// Deprecated: use CaptureRuntimeMetricsFunc instead, which reads the runtime/metrics package and does not stop the
+// world to collect memory statistics.
 func CaptureRuntimeMemStatsFunc(registry RootRegistry) (func(), bool) {
 	mRegProvider, ok := registry.(metricsRegistryProvider)
 	if !ok {
@@ -175,6 +182,11 @@ type rootRegistry struct {
 
 	// mutex lock to protect metric map concurrent writes
 	idToMetricMutex sync.RWMutex
+
+	// schema that registered metrics must conform to. If nil, any metric may be registered.
+	schema *Schema
+	// called with the validation error when a metric that does not conform to the schema is requested.
+	onSchemaViolation func(error)
 }
 
 type childRegistry struct {
@@ -329,33 +341,67 @@ func (r *rootRegistry) Unregister(name string, tags ...Tag) {
 }
 
 func (r *rootRegistry) Counter(name string, tags ...Tag) metrics.Counter {
+	if !r.conformsToSchema(name, MetricTypeCounter, tags) {
+		return metrics.NilCounter{}
+	}
 	return metrics.GetOrRegisterCounter(r.registerMetric(name, tags), r.registry)
 }
 
 func (r *rootRegistry) Gauge(name string, tags ...Tag) metrics.Gauge {
+	if !r.conformsToSchema(name, MetricTypeGauge, tags) {
+		return metrics.NilGauge{}
+	}
 	return metrics.GetOrRegisterGauge(r.registerMetric(name, tags), r.registry)
 }
 
 func (r *rootRegistry) GaugeFloat64(name string, tags ...Tag) metrics.GaugeFloat64 {
+	if !r.conformsToSchema(name, MetricTypeGauge, tags) {
+		return metrics.NilGaugeFloat64{}
+	}
 	return metrics.GetOrRegisterGaugeFloat64(r.registerMetric(name, tags), r.registry)
 }
 
 func (r *rootRegistry) Meter(name string, tags ...Tag) metrics.Meter {
+	if !r.conformsToSchema(name, MetricTypeMeter, tags) {
+		return metrics.NilMeter{}
+	}
 	return metrics.GetOrRegisterMeter(r.registerMetric(name, tags), r.registry)
 }
 
 func (r *rootRegistry) Timer(name string, tags ...Tag) metrics.Timer {
+	if !r.conformsToSchema(name, MetricTypeTimer, tags) {
+		return metrics.NilTimer{}
+	}
 	return getOrRegisterMicroSecondsTimer(r.registerMetric(name, tags), r.registry)
 }
 
 func (r *rootRegistry) Histogram(name string, tags ...Tag) metrics.Histogram {
+	if !r.conformsToSchema(name, MetricTypeHistogram, tags) {
+		return metrics.NilHistogram{}
+	}
 	return getOrRegisterHistogram(r.registerMetric(name, tags), r.registry)
 }
 
 func (r *rootRegistry) HistogramWithSample(name string, sample metrics.Sample, tags ...Tag) metrics.Histogram {
+	if !r.conformsToSchema(name, MetricTypeHistogram, tags) {
+		return metrics.NilHistogram{}
+	}
 	return metrics.GetOrRegisterHistogram(r.registerMetric(name, tags), r.registry, sample)
 }
 
+// conformsToSchema returns true if the registry does not have a schema or if the provided metric conforms to it.
+// Otherwise, the violation is reported to the registry's violation handler and false is returned.
+func (r *rootRegistry) conformsToSchema(name string, metricType MetricType, tags Tags) bool {
+	if r.schema == nil {
+		return true
+	}
+	if err := r.schema.Validate(name, metricType, tags); err != nil {
+		r.onSchemaViolation(err)
+		return false
+	}
+	return true
+}
+
 func (r *rootRegistry) Registry() metrics.Registry {
 	return r.registry
 }
@@ -385,6 +431,25 @@ func (r *rootRegistry) registerMetric(name string, tags Tags) string {
 	return string(metricID)
 }
 
+// registerMetricWithTagSet is equivalent to registerMetric, but uses the identifier cached on the TagSet and only takes
+// the write lock if the metric has not already been registered, so registering an existing metric does not allocate.
+func (r *rootRegistry) registerMetricWithTagSet(name string, tags TagSet) string {
+	s := tags.set()
+	metricID := s.metricID(name)
+	r.idToMetricMutex.RLock()
+	_, ok := r.idToMetricWithTags[metricID]
+	r.idToMetricMutex.RUnlock()
+	if !ok {
+		r.idToMetricMutex.Lock()
+		r.idToMetricWithTags[metricID] = metricWithTags{
+			name: name,
+			tags: s.tags,
+		}
+		r.idToMetricMutex.Unlock()
+	}
+	return string(metricID)
+}
+
 // metricWithTags stores a specific metric with its set of tags.
 type metricWithTags struct {
 	name string
@@ -0,0 +1,221 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"math"
+	runtimemetrics "runtime/metrics"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const runtimeMetricPrefix = "go.runtime."
+
+// DefaultRuntimeMetrics is the set of runtime/metrics sample names collected by CaptureRuntimeMetricsWithContext and
+// CaptureRuntimeMetricsFunc when no allowlist is provided. Names that are not supported by the running version of Go
+// are skipped.
+var DefaultRuntimeMetrics = []string{
+	"/gc/cycles/total:gc-cycles",
+	"/gc/heap/goal:bytes",
+	"/gc/heap/objects:objects",
+	"/memory/classes/heap/free:bytes",
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/heap/released:bytes",
+	"/memory/classes/heap/stacks:bytes",
+	"/memory/classes/heap/unused:bytes",
+	"/memory/classes/total:bytes",
+	"/sched/gomaxprocs:threads",
+	"/sched/goroutines:goroutines",
+	"/sched/latencies:seconds",
+	"/sched/pauses/total/gc:seconds",
+}
+
+// RuntimeMetricsParam configures the collector created by CaptureRuntimeMetricsWithContext and
+// CaptureRuntimeMetricsFunc.
+type RuntimeMetricsParam func(*runtimeMetricsCollector)
+
+// RuntimeMetricsAllowlist sets the runtime/metrics sample names (for example, "/sched/goroutines:goroutines") that
+// are collected. Every name must be supported by the running version of Go. If this parameter is not provided,
+// DefaultRuntimeMetrics is used.
+func RuntimeMetricsAllowlist(names ...string) RuntimeMetricsParam {
+	return func(c *runtimeMetricsCollector) {
+		c.allowlist = names
+		c.explicitAllowlist = true
+	}
+}
+
+// RuntimeMetricsTags sets tags that are added to every runtime metric registered by the collector.
+func RuntimeMetricsTags(tags ...Tag) RuntimeMetricsParam {
+	return func(c *runtimeMetricsCollector) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// CaptureRuntimeMetricsWithContext registers Go runtime metrics read from the standard runtime/metrics package on the
+// provided registry and starts a goroutine that updates them every collectionFreq until the provided context is done.
+// Unlike CaptureRuntimeMemStatsWithContext, reading runtime/metrics does not stop the world.
+//
+// Each sample is registered with the name "go.runtime.<path>.<unit>", where path and unit are derived from the
+// runtime/metrics name: "/sched/goroutines:goroutines" is registered as "go.runtime.sched.goroutines.goroutines".
+// Scalar samples are registered as gauges. Histogram samples (such as GC pauses and scheduler latencies) are
+// summarized over each collection interval and registered as the gauges "<name>.count", "<name>.p50", "<name>.p99"
+// and "<name>.max".
+//
+// Returns an error if an allowlist is provided that contains a name that is not supported by the running version of
+// Go. In that case, no goroutine is started.
+func CaptureRuntimeMetricsWithContext(ctx context.Context, registry RootRegistry, collectionFreq time.Duration, params ...RuntimeMetricsParam) error {
+	capture, err := CaptureRuntimeMetricsFunc(registry, params...)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(collectionFreq)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				capture()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// CaptureRuntimeMetricsFunc returns a function that reads the configured runtime/metrics samples and records them on
+// the provided registry when called. See CaptureRuntimeMetricsWithContext for details on how samples are recorded.
+// The returned function is safe for concurrent use.
+func CaptureRuntimeMetricsFunc(registry RootRegistry, params ...RuntimeMetricsParam) (func(), error) {
+	c := &runtimeMetricsCollector{
+		registry:  registry,
+		allowlist: DefaultRuntimeMetrics,
+	}
+	for _, param := range params {
+		if param != nil {
+			param(c)
+		}
+	}
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return c.capture, nil
+}
+
+type runtimeMetricsCollector struct {
+	registry          Registry
+	tags              Tags
+	allowlist         []string
+	explicitAllowlist bool
+
+	mutex   sync.Mutex
+	samples []runtimemetrics.Sample
+	// previous cumulative histogram bucket counts keyed by sample name, used to compute per-interval deltas.
+	prevCounts map[string][]uint64
+}
+
+func (c *runtimeMetricsCollector) init() error {
+	supported := make(map[string]runtimemetrics.Description)
+	for _, desc := range runtimemetrics.All() {
+		supported[desc.Name] = desc
+	}
+	seen := make(map[string]struct{})
+	for _, name := range c.allowlist {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		desc, ok := supported[name]
+		if !ok || desc.Kind == runtimemetrics.KindBad {
+			if c.explicitAllowlist {
+				return errors.Errorf("runtime metric %q is not supported by this version of Go", name)
+			}
+			continue
+		}
+		c.samples = append(c.samples, runtimemetrics.Sample{Name: name})
+	}
+	c.prevCounts = make(map[string][]uint64)
+	return nil
+}
+
+func (c *runtimeMetricsCollector) capture() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	runtimemetrics.Read(c.samples)
+	for _, sample := range c.samples {
+		name := runtimeMetricName(sample.Name)
+		switch sample.Value.Kind() {
+		case runtimemetrics.KindUint64:
+			c.registry.Gauge(name, c.tags...).Update(int64(sample.Value.Uint64()))
+		case runtimemetrics.KindFloat64:
+			c.registry.GaugeFloat64(name, c.tags...).Update(sample.Value.Float64())
+		case runtimemetrics.KindFloat64Histogram:
+			c.captureHistogram(sample.Name, name, sample.Value.Float64Histogram())
+		}
+	}
+}
+
+// captureHistogram records a summary of the observations added to the cumulative histogram since the previous call.
+func (c *runtimeMetricsCollector) captureHistogram(sampleName, name string, hist *runtimemetrics.Float64Histogram) {
+	prev := c.prevCounts[sampleName]
+	delta := make([]uint64, len(hist.Counts))
+	var total uint64
+	for i, count := range hist.Counts {
+		if len(prev) == len(hist.Counts) {
+			count -= prev[i]
+		}
+		delta[i] = count
+		total += count
+	}
+	// the runtime may reuse the memory backing the histogram, so store a copy
+	c.prevCounts[sampleName] = append(prev[:0], hist.Counts...)
+
+	c.registry.Gauge(name+".count", c.tags...).Update(int64(total))
+	c.registry.GaugeFloat64(name+".p50", c.tags...).Update(histogramQuantile(delta, hist.Buckets, 0.5))
+	c.registry.GaugeFloat64(name+".p99", c.tags...).Update(histogramQuantile(delta, hist.Buckets, 0.99))
+	c.registry.GaugeFloat64(name+".max", c.tags...).Update(histogramQuantile(delta, hist.Buckets, 1))
+}
+
+// histogramQuantile returns an upper bound for the q-th quantile of the observations in counts, where counts[i] is the
+// number of observations in the bucket [buckets[i], buckets[i+1]). Returns 0 if there are no observations.
+func histogramQuantile(counts []uint64, buckets []float64, q float64) float64 {
+	var total uint64
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	threshold := uint64(math.Ceil(q * float64(total)))
+	if threshold == 0 {
+		threshold = 1
+	}
+	var cumulative uint64
+	for i, count := range counts {
+		cumulative += count
+		if cumulative < threshold {
+			continue
+		}
+		if upper := buckets[i+1]; !math.IsInf(upper, 1) {
+			return upper
+		}
+		return buckets[i]
+	}
+	return buckets[len(buckets)-1]
+}
+
+// runtimeMetricName converts a runtime/metrics sample name of the form "/path/to/metric:unit" into the metric name
+// "go.runtime.path.to.metric.unit".
+func runtimeMetricName(sampleName string) string {
+	name := strings.TrimPrefix(sampleName, "/")
+	name = strings.ReplaceAll(name, "/", ".")
+	name = strings.Replace(name, ":", ".", 1)
+	return runtimeMetricPrefix + name
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := []float64{math.Inf(-1), 1, 2, 4, math.Inf(1)}
+	for _, tc := range []struct {
+		name   string
+		counts []uint64
+		q      float64
+		want   float64
+	}{
+		{name: "empty", counts: []uint64{0, 0, 0, 0}, q: 0.5, want: 0},
+		{name: "median", counts: []uint64{0, 5, 4, 1}, q: 0.5, want: 2},
+		{name: "p99", counts: []uint64{0, 5, 4, 1}, q: 0.99, want: 4},
+		{name: "max in finite bucket", counts: []uint64{0, 5, 4, 0}, q: 1, want: 4},
+		{name: "max in unbounded bucket uses lower bound", counts: []uint64{0, 0, 0, 3}, q: 1, want: 4},
+		{name: "zero quantile", counts: []uint64{0, 0, 2, 0}, q: 0, want: 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, histogramQuantile(tc.counts, buckets, tc.q))
+		})
+	}
+}
+
+func TestRuntimeMetricName(t *testing.T) {
+	assert.Equal(t, "go.runtime.sched.goroutines.goroutines", runtimeMetricName("/sched/goroutines:goroutines"))
+	assert.Equal(t, "go.runtime.gc.heap.allocs.bytes", runtimeMetricName("/gc/heap/allocs:bytes"))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureRuntimeMetricsFunc(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	capture, err := metrics.CaptureRuntimeMetricsFunc(root,
+		metrics.RuntimeMetricsAllowlist("/sched/goroutines:goroutines", "/sched/pauses/total/gc:seconds"),
+		metrics.RuntimeMetricsTags(metrics.MustNewTag("service", "test")),
+	)
+	require.NoError(t, err)
+
+	runtime.GC()
+	capture()
+
+	gotNames := make(map[string]metrics.MetricVal)
+	root.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		assert.Equal(t, metrics.Tags{metrics.MustNewTag("service", "test")}, tags)
+		gotNames[name] = value
+	})
+	assert.Len(t, gotNames, 5)
+	assert.Contains(t, gotNames, "go.runtime.sched.pauses.total.gc.seconds.count")
+	assert.Contains(t, gotNames, "go.runtime.sched.pauses.total.gc.seconds.p50")
+	assert.Contains(t, gotNames, "go.runtime.sched.pauses.total.gc.seconds.p99")
+	assert.Contains(t, gotNames, "go.runtime.sched.pauses.total.gc.seconds.max")
+	require.Contains(t, gotNames, "go.runtime.sched.goroutines.goroutines")
+	assert.Equal(t, "gauge", gotNames["go.runtime.sched.goroutines.goroutines"].Type())
+	assert.Greater(t, gotNames["go.runtime.sched.goroutines.goroutines"].Value("value"), int64(0))
+	assert.Greater(t, gotNames["go.runtime.sched.pauses.total.gc.seconds.count"].Value("value"), int64(0))
+}
+
+func TestCaptureRuntimeMetricsFunc_DefaultMetrics(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	capture, err := metrics.CaptureRuntimeMetricsFunc(root)
+	require.NoError(t, err)
+	capture()
+
+	var gotNames []string
+	root.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		gotNames = append(gotNames, name)
+	})
+	assert.Contains(t, gotNames, "go.runtime.memory.classes.heap.objects.bytes")
+	assert.Contains(t, gotNames, "go.runtime.sched.latencies.seconds.p99")
+	assert.Contains(t, gotNames, "go.runtime.gc.cycles.total.gc-cycles")
+}
+
+func TestCaptureRuntimeMetricsWithContext_UnsupportedMetric(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	err := metrics.CaptureRuntimeMetricsWithContext(context.Background(), root, time.Hour, metrics.RuntimeMetricsAllowlist("/not/a/metric:bytes"))
+	assert.EqualError(t, err, `runtime metric "/not/a/metric:bytes" is not supported by this version of Go`)
+
+	var gotNames []string
+	root.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		gotNames = append(gotNames, name)
+	})
+	assert.Empty(t, gotNames)
+}
+
+func TestCaptureRuntimeMetricsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	root := metrics.NewRootMetricsRegistry()
+	err := metrics.CaptureRuntimeMetricsWithContext(ctx, root, time.Millisecond, metrics.RuntimeMetricsAllowlist("/sched/goroutines:goroutines"))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return root.Gauge("go.runtime.sched.goroutines.goroutines").Value() > 0
+	}, time.Second, time.Millisecond)
+}
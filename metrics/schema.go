@@ -0,0 +1,231 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// MetricType is the type of a metric declared in a Schema. The values match the values returned by MetricVal.Type.
+type MetricType string
+
+const (
+	MetricTypeCounter   MetricType = "counter"
+	MetricTypeGauge     MetricType = "gauge"
+	MetricTypeHistogram MetricType = "histogram"
+	MetricTypeMeter     MetricType = "meter"
+	MetricTypeTimer     MetricType = "timer"
+)
+
+func (t MetricType) valid() bool {
+	switch t {
+	case MetricTypeCounter, MetricTypeGauge, MetricTypeHistogram, MetricTypeMeter, MetricTypeTimer:
+		return true
+	default:
+		return false
+	}
+}
+
+// MetricDefinition declares a metric that may be registered on a registry that enforces a Schema.
+type MetricDefinition struct {
+	// Name is the full name of the metric as it is registered on the root registry (including any subregistry prefix).
+	Name string `json:"name" yaml:"name"`
+	// Type is the type of the metric. Gauges registered using Gauge and GaugeFloat64 both have type MetricTypeGauge.
+	Type MetricType `json:"type" yaml:"type"`
+	// Description is a human-readable description of what the metric measures.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Unit is the unit of the metric's values, for example "bytes" or "microseconds".
+	Unit string `json:"unit,omitempty" yaml:"unit,omitempty"`
+	// TagKeys are the tag keys that may be set on the metric. A metric may be registered with any subset of these keys.
+	TagKeys []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Schema is an immutable set of metric definitions keyed by metric name.
+type Schema struct {
+	definitions map[string]MetricDefinition
+	sortedNames []string
+}
+
+// NewSchema returns a Schema containing the provided definitions. Returns an error if any definition does not have a
+// name or has an unknown type, or if multiple definitions have the same name.
+func NewSchema(definitions ...MetricDefinition) (*Schema, error) {
+	s := &Schema{
+		definitions: make(map[string]MetricDefinition, len(definitions)),
+	}
+	for _, def := range definitions {
+		if def.Name == "" {
+			return nil, errors.New("metric definition must have a name")
+		}
+		if !def.Type.valid() {
+			return nil, errors.Errorf("metric %q has unknown type %q", def.Name, def.Type)
+		}
+		if _, ok := s.definitions[def.Name]; ok {
+			return nil, errors.Errorf("metric %q is defined more than once", def.Name)
+		}
+		for _, key := range def.TagKeys {
+			if key == "" {
+				return nil, errors.Errorf("metric %q has an empty tag key", def.Name)
+			}
+		}
+		def.TagKeys = append([]string(nil), def.TagKeys...)
+		s.definitions[def.Name] = def
+		s.sortedNames = append(s.sortedNames, def.Name)
+	}
+	sortStrings(s.sortedNames)
+	return s, nil
+}
+
+// MustNewSchema returns the result of calling NewSchema, but panics if NewSchema returns an error. Should only be used
+// in instances where the definitions are statically defined and known to be valid.
+func MustNewSchema(definitions ...MetricDefinition) *Schema {
+	s, err := NewSchema(definitions...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// LoadSchema returns a Schema containing the definitions in the provided YAML. The YAML must have the form:
+//
+//	metrics:
+//	  - name: server.response
+//	    type: timer
+//	    description: Time taken to respond to a request.
+//	    unit: microseconds
+//	    tags: [endpoint, method]
+func LoadSchema(yamlBytes []byte) (*Schema, error) {
+	var cfg struct {
+		Metrics []MetricDefinition `yaml:"metrics"`
+	}
+	if err := yaml.Unmarshal(yamlBytes, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal metric schema")
+	}
+	return NewSchema(cfg.Metrics...)
+}
+
+// Definitions returns the definitions in the schema in sorted order of name.
+func (s *Schema) Definitions() []MetricDefinition {
+	defs := make([]MetricDefinition, 0, len(s.sortedNames))
+	for _, name := range s.sortedNames {
+		defs = append(defs, s.definitions[name])
+	}
+	return defs
+}
+
+// Lookup returns the definition for the metric with the provided name.
+func (s *Schema) Lookup(name string) (MetricDefinition, bool) {
+	def, ok := s.definitions[name]
+	return def, ok
+}
+
+// Validate returns an error if a metric with the provided name, type and tags does not conform to the schema. A metric
+// conforms if it is declared with the same type and every one of its tag keys is declared for the metric.
+func (s *Schema) Validate(name string, metricType MetricType, tags Tags) error {
+	def, ok := s.definitions[name]
+	if !ok {
+		return errors.Errorf("metric %q is not declared in the schema", name)
+	}
+	if def.Type != metricType {
+		return errors.Errorf("metric %q is declared as type %q but was registered as type %q", name, def.Type, metricType)
+	}
+	for _, tag := range tags {
+		if !slices.Contains(def.TagKeys, tag.key) {
+			return errors.Errorf("metric %q does not declare tag key %q: allowed keys are %v", name, tag.key, def.TagKeys)
+		}
+	}
+	return nil
+}
+
+// metricNameRegexp matches metric names that consist of one or more segments separated by periods, where each segment
+// starts with a lowercase letter and contains only lowercase letters, digits, hyphens and underscores.
+var metricNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_-]*(\.[a-z][a-z0-9_-]*)*$`)
+
+// Lint returns the problems with the definitions in the schema that do not prevent it from being used but that are
+// likely to be mistakes: names that are not lowercase and period-separated, missing descriptions, and tag keys that are
+// duplicated or that are not in the normalized form produced by NewTag. A tag key that is not normalized can never be
+// registered, since the keys of registered tags are always normalized. The problems are returned in sorted order of
+// metric name.
+func (s *Schema) Lint() []error {
+	var problems []error
+	for _, def := range s.Definitions() {
+		if !metricNameRegexp.MatchString(def.Name) {
+			problems = append(problems, errors.Errorf("metric %q: name should consist of lowercase period-separated segments", def.Name))
+		}
+		if strings.TrimSpace(def.Description) == "" {
+			problems = append(problems, errors.Errorf("metric %q: description is empty", def.Name))
+		}
+		seen := make(map[string]struct{}, len(def.TagKeys))
+		for _, key := range def.TagKeys {
+			if _, ok := seen[key]; ok {
+				problems = append(problems, errors.Errorf("metric %q: tag key %q is declared more than once", def.Name, key))
+			}
+			seen[key] = struct{}{}
+			if first := key[0]; first < 'a' || first > 'z' {
+				problems = append(problems, errors.Errorf("metric %q: tag key %q must start with a lowercase letter", def.Name, key))
+			} else if normalized := normalizeTag(key, validKeyChars); normalized != key {
+				problems = append(problems, errors.Errorf("metric %q: tag key %q is not normalized: registered tags use the key %q", def.Name, key, normalized))
+			}
+		}
+	}
+	return problems
+}
+
+// WriteMarkdown writes a Markdown table that documents every metric in the schema to the provided writer.
+func (s *Schema) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("| Name | Type | Unit | Tags | Description |\n")
+	sb.WriteString("| ---- | ---- | ---- | ---- | ----------- |\n")
+	for _, def := range s.Definitions() {
+		tagKeys := make([]string, len(def.TagKeys))
+		for i, key := range def.TagKeys {
+			tagKeys[i] = "`" + key + "`"
+		}
+		_, _ = fmt.Fprintf(&sb, "| `%s` | %s | %s | %s | %s |\n",
+			def.Name,
+			def.Type,
+			escapeMarkdownTableCell(def.Unit),
+			strings.Join(tagKeys, ", "),
+			escapeMarkdownTableCell(def.Description),
+		)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// NewRootMetricsRegistryWithSchema creates a new root registry that only allows metrics that conform to the provided
+// schema to be registered. When a metric that does not conform is requested, onViolation is called with an error that
+// describes the violation and a no-op metric is returned so that the metric is never emitted. If onViolation is nil,
+// non-conforming metrics are dropped without being reported. Tests can provide PanicOnSchemaViolation to fail on the
+// first violation.
+//
+// Tags added to registries returned by FromContext and Subregistry are validated in the same manner as tags provided
+// directly to the root registry.
+func NewRootMetricsRegistryWithSchema(schema *Schema, onViolation func(error)) RootRegistry {
+	if onViolation == nil {
+		onViolation = func(error) {}
+	}
+	root := NewRootMetricsRegistry().(*rootRegistry)
+	root.schema = schema
+	root.onSchemaViolation = onViolation
+	return root
+}
+
+// PanicOnSchemaViolation is a violation handler for NewRootMetricsRegistryWithSchema that panics with the violation.
+// It should only be used in tests, where an undeclared metric should fail the test rather than be dropped.
+func PanicOnSchemaViolation(err error) {
+	panic(err)
+}
+
+func escapeMarkdownTableCell(in string) string {
+	in = strings.ReplaceAll(in, "|", `\|`)
+	return strings.ReplaceAll(in, "\n", " ")
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchemaYAML = `
+metrics:
+  - name: server.response
+    type: timer
+    description: Time taken to respond to a request.
+    unit: microseconds
+    tags: [endpoint, method]
+  - name: cache.size
+    type: gauge
+    description: Number of entries | in the cache.
+    tags: [cache]
+`
+
+func TestLoadSchema(t *testing.T) {
+	schema, err := metrics.LoadSchema([]byte(testSchemaYAML))
+	require.NoError(t, err)
+
+	assert.Equal(t, []metrics.MetricDefinition{
+		{
+			Name:        "cache.size",
+			Type:        metrics.MetricTypeGauge,
+			Description: "Number of entries | in the cache.",
+			TagKeys:     []string{"cache"},
+		},
+		{
+			Name:        "server.response",
+			Type:        metrics.MetricTypeTimer,
+			Description: "Time taken to respond to a request.",
+			Unit:        "microseconds",
+			TagKeys:     []string{"endpoint", "method"},
+		},
+	}, schema.Definitions())
+}
+
+func TestNewSchema_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		defs    []metrics.MetricDefinition
+		wantErr string
+	}{
+		{
+			name:    "missing name",
+			defs:    []metrics.MetricDefinition{{Type: metrics.MetricTypeCounter}},
+			wantErr: "metric definition must have a name",
+		},
+		{
+			name:    "unknown type",
+			defs:    []metrics.MetricDefinition{{Name: "my-metric", Type: "summary"}},
+			wantErr: `metric "my-metric" has unknown type "summary"`,
+		},
+		{
+			name: "duplicate name",
+			defs: []metrics.MetricDefinition{
+				{Name: "my-metric", Type: metrics.MetricTypeCounter},
+				{Name: "my-metric", Type: metrics.MetricTypeGauge},
+			},
+			wantErr: `metric "my-metric" is defined more than once`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := metrics.NewSchema(tc.defs...)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSchema_Lint(t *testing.T) {
+	schema := metrics.MustNewSchema(
+		metrics.MetricDefinition{Name: "server.response", Type: metrics.MetricTypeTimer, Description: "Response time.", TagKeys: []string{"endpoint"}},
+		metrics.MetricDefinition{Name: "Server.Requests", Type: metrics.MetricTypeMeter, Description: "Requests."},
+		metrics.MetricDefinition{Name: "cache.size", Type: metrics.MetricTypeGauge, TagKeys: []string{"Cache Name", "cache", "cache", "1st"}},
+	)
+	var problems []string
+	for _, err := range schema.Lint() {
+		problems = append(problems, err.Error())
+	}
+	assert.Equal(t, []string{
+		`metric "Server.Requests": name should consist of lowercase period-separated segments`,
+		`metric "cache.size": description is empty`,
+		`metric "cache.size": tag key "Cache Name" must start with a lowercase letter`,
+		`metric "cache.size": tag key "cache" is declared more than once`,
+		`metric "cache.size": tag key "1st" must start with a lowercase letter`,
+	}, problems)
+
+	schema = metrics.MustNewSchema(metrics.MetricDefinition{Name: "cache.size", Type: metrics.MetricTypeGauge, Description: "Size.", TagKeys: []string{"cache name"}})
+	require.Len(t, schema.Lint(), 1)
+	assert.EqualError(t, schema.Lint()[0], `metric "cache.size": tag key "cache name" is not normalized: registered tags use the key "cache_name"`)
+}
+
+func TestSchema_WriteMarkdown(t *testing.T) {
+	schema, err := metrics.LoadSchema([]byte(testSchemaYAML))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, schema.WriteMarkdown(&buf))
+	assert.Equal(t, "| Name | Type | Unit | Tags | Description |\n"+
+		"| ---- | ---- | ---- | ---- | ----------- |\n"+
+		"| `cache.size` | gauge |  | `cache` | Number of entries \\| in the cache. |\n"+
+		"| `server.response` | timer | microseconds | `endpoint`, `method` | Time taken to respond to a request. |\n",
+		buf.String())
+}
+
+func TestRootRegistryWithSchema(t *testing.T) {
+	schema, err := metrics.LoadSchema([]byte(testSchemaYAML))
+	require.NoError(t, err)
+
+	var violations []string
+	root := metrics.NewRootMetricsRegistryWithSchema(schema, func(err error) {
+		violations = append(violations, err.Error())
+	})
+
+	root.Timer("server.response", metrics.MustNewTag("endpoint", "get-foo")).Update(1)
+	root.GaugeFloat64("cache.size").Update(1)
+	ctx := metrics.WithRegistry(context.Background(), root)
+	ctx = metrics.AddTags(ctx, metrics.MustNewTag("cache", "users"))
+	metrics.FromContext(ctx).Gauge("cache.size").Update(2)
+
+	// violations: undeclared metric, wrong type, undeclared tag (including tags from context)
+	root.Counter("undeclared").Inc(1)
+	root.Counter("server.response").Inc(1)
+	root.Timer("server.response", metrics.MustNewTag("status", "200")).Update(1)
+	metrics.FromContext(ctx).Timer("server.response").Update(1)
+
+	assert.Equal(t, []string{
+		`metric "undeclared" is not declared in the schema`,
+		`metric "server.response" is declared as type "timer" but was registered as type "counter"`,
+		`metric "server.response" does not declare tag key "status": allowed keys are [endpoint method]`,
+		`metric "server.response" does not declare tag key "cache": allowed keys are [endpoint method]`,
+	}, violations)
+
+	var gotNames []string
+	root.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		gotNames = append(gotNames, name+":"+value.Type())
+	})
+	assert.Equal(t, []string{"cache.size:gauge", "cache.size:gauge", "server.response:timer"}, gotNames)
+}
+
+func TestRootRegistryWithSchema_DropsWithoutHandler(t *testing.T) {
+	root := metrics.NewRootMetricsRegistryWithSchema(metrics.MustNewSchema(), nil)
+	assert.NotPanics(t, func() {
+		root.Counter("undeclared").Inc(1)
+	})
+	root.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		t.Errorf("unexpected metric %s", name)
+	})
+}
+
+func TestRootRegistryWithSchema_PanicOnSchemaViolation(t *testing.T) {
+	root := metrics.NewRootMetricsRegistryWithSchema(metrics.MustNewSchema(), metrics.PanicOnSchemaViolation)
+	assert.PanicsWithError(t, `metric "undeclared" is not declared in the schema`, func() {
+		root.Counter("undeclared")
+	})
+}
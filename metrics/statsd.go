@@ -0,0 +1,278 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// StatsDFormat is the wire format used by a StatsDEmitter.
+type StatsDFormat int
+
+const (
+	// StatsDFormatStatsD is the plain StatsD format. Because StatsD does not support tags, tags are appended to the
+	// metric name as ".<key>.<value>" in sorted order.
+	StatsDFormatStatsD StatsDFormat = iota
+	// StatsDFormatDogStatsD is the DogStatsD format, which encodes tags natively as "|#<key>:<value>,...".
+	StatsDFormatDogStatsD
+)
+
+// DefaultStatsDMaxPacketSize is the default maximum size of a packet sent by a StatsDEmitter. It is chosen so that
+// packets fit in the payload of a single UDP datagram on a network with a 1500 byte MTU.
+const DefaultStatsDMaxPacketSize = 1432
+
+// StatsDEmitter sends the values of the metrics in a Registry to a StatsD or DogStatsD agent over UDP or a Unix
+// datagram socket. Lines are batched into packets of at most the configured maximum packet size.
+//
+// Counters are sent as StatsD counters whose value is the change in the count since the previous emission. Gauges are
+// sent as gauges with the metric's name. For all other metric types, each of the metric's values (as returned by
+// MetricVal.Keys) is sent as a gauge named "<name>.<key>", for example "server.response.p95".
+type StatsDEmitter struct {
+	conn          net.Conn
+	prefix        string
+	format        StatsDFormat
+	maxPacketSize int
+	onError       func(error)
+
+	mutex sync.Mutex
+	buf   []byte
+	// previous counts of counters keyed by the encoded metric name and tags.
+	prevCounts map[string]int64
+}
+
+// StatsDParam configures a StatsDEmitter.
+type StatsDParam func(*StatsDEmitter)
+
+// StatsDPrefix sets a prefix that is prepended to the name of every emitted metric. A "." is added to the prefix if it
+// does not already end with one.
+func StatsDPrefix(prefix string) StatsDParam {
+	return func(e *StatsDEmitter) {
+		if prefix != "" && !strings.HasSuffix(prefix, ".") {
+			prefix = prefix + "."
+		}
+		e.prefix = prefix
+	}
+}
+
+// StatsDWithFormat sets the wire format used by the emitter. The default is StatsDFormatStatsD.
+func StatsDWithFormat(format StatsDFormat) StatsDParam {
+	return func(e *StatsDEmitter) {
+		e.format = format
+	}
+}
+
+// StatsDMaxPacketSize sets the maximum size in bytes of a packet sent by the emitter. A line that is longer than the
+// maximum size is sent in a packet of its own. The default is DefaultStatsDMaxPacketSize.
+func StatsDMaxPacketSize(size int) StatsDParam {
+	return func(e *StatsDEmitter) {
+		e.maxPacketSize = size
+	}
+}
+
+// StatsDErrorHandler sets a function that is called with errors encountered while sending packets when the emitter is
+// run using Run. By default, such errors are ignored.
+func StatsDErrorHandler(onError func(error)) StatsDParam {
+	return func(e *StatsDEmitter) {
+		e.onError = onError
+	}
+}
+
+// NewStatsDEmitter returns an emitter that sends metrics to the provided address. The network must be "udp", "udp4",
+// "udp6" or "unixgram".
+func NewStatsDEmitter(network, address string, params ...StatsDParam) (*StatsDEmitter, error) {
+	switch network {
+	case "udp", "udp4", "udp6", "unixgram":
+	default:
+		return nil, errors.Errorf("unsupported network %q: must be one of udp, udp4, udp6 or unixgram", network)
+	}
+	e := &StatsDEmitter{
+		maxPacketSize: DefaultStatsDMaxPacketSize,
+		prevCounts:    make(map[string]int64),
+	}
+	for _, param := range params {
+		if param != nil {
+			param(e)
+		}
+	}
+	if e.maxPacketSize <= 0 {
+		return nil, errors.Errorf("max packet size must be positive, was %d", e.maxPacketSize)
+	}
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to StatsD agent at %s", address)
+	}
+	e.conn = conn
+	e.buf = make([]byte, 0, e.maxPacketSize)
+	return e, nil
+}
+
+// Run calls Emit with the provided registry every emitFrequency. Run blocks until ctx is cancelled and should be
+// started in its own goroutine. Errors returned by Emit are passed to the handler set using StatsDErrorHandler.
+func (e *StatsDEmitter) Run(ctx context.Context, registry Registry, emitFrequency time.Duration) {
+	t := time.NewTicker(emitFrequency)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := e.Emit(registry); err != nil && e.onError != nil {
+				e.onError(err)
+			}
+		}
+	}
+}
+
+// Emit sends the current values of all of the metrics in the provided registry. If sending a packet fails, Emit
+// continues to send the remaining packets and returns the first error encountered.
+func (e *StatsDEmitter) Emit(registry Registry) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var firstErr error
+	write := func(name string, tags Tags, value []byte, metricType string) {
+		if err := e.writeLine(e.encodeLine(name, tags, value, metricType)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	registry.Each(func(name string, tags Tags, value MetricVal) {
+		name = e.prefix + name
+		switch metricType := value.Type(); metricType {
+		case "counter":
+			count, ok := value.Value("count").(int64)
+			if !ok {
+				return
+			}
+			id := string(e.encodeLine(name, tags, nil, ""))
+			delta := count - e.prevCounts[id]
+			e.prevCounts[id] = count
+			write(name, tags, strconv.AppendInt(nil, delta, 10), "c")
+		default:
+			for key := range value.Keys() {
+				val, ok := formatStatsDValue(value.Value(key))
+				if !ok {
+					continue
+				}
+				metricName := name
+				if metricType != "gauge" {
+					metricName += "." + key
+				}
+				if val[0] == '-' {
+					// a gauge value with a leading sign is interpreted as a change, so reset the gauge to zero first
+					write(metricName, tags, []byte("0"), "g")
+				}
+				write(metricName, tags, val, "g")
+			}
+		}
+	})
+	if err := e.flush(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// Close closes the connection to the StatsD agent.
+func (e *StatsDEmitter) Close() error {
+	return e.conn.Close()
+}
+
+// encodeLine returns the line "<name>:<value>|<type>" for a metric. For the StatsD format, the tags are appended to the
+// name; for the DogStatsD format, they are appended to the line as "|#<key>:<value>,...".
+func (e *StatsDEmitter) encodeLine(name string, tags Tags, value []byte, metricType string) []byte {
+	line := appendStatsDName(nil, name)
+	if e.format == StatsDFormatStatsD {
+		for _, tag := range tags {
+			line = append(line, '.')
+			line = appendStatsDName(line, tag.key)
+			line = append(line, '.')
+			line = appendStatsDName(line, tag.value)
+		}
+	}
+	line = append(line, ':')
+	line = append(line, value...)
+	line = append(line, '|')
+	line = append(line, metricType...)
+	if e.format == StatsDFormatDogStatsD && len(tags) > 0 {
+		line = append(line, "|#"...)
+		for i, tag := range tags {
+			if i > 0 {
+				line = append(line, ',')
+			}
+			line = appendDogStatsDTag(line, tag.key)
+			line = append(line, ':')
+			line = appendDogStatsDTag(line, tag.value)
+		}
+	}
+	return line
+}
+
+// writeLine adds the provided line to the current packet, sending the packet first if the line does not fit in it.
+func (e *StatsDEmitter) writeLine(line []byte) error {
+	var err error
+	if len(e.buf) > 0 && len(e.buf)+1+len(line) > e.maxPacketSize {
+		err = e.flush()
+	}
+	if len(e.buf) > 0 {
+		e.buf = append(e.buf, '\n')
+	}
+	e.buf = append(e.buf, line...)
+	return err
+}
+
+func (e *StatsDEmitter) flush() error {
+	if len(e.buf) == 0 {
+		return nil
+	}
+	_, err := e.conn.Write(e.buf)
+	e.buf = e.buf[:0]
+	if err != nil {
+		return errors.Wrapf(err, "failed to send metrics to StatsD agent")
+	}
+	return nil
+}
+
+func formatStatsDValue(val interface{}) ([]byte, bool) {
+	switch v := val.(type) {
+	case int64:
+		return strconv.AppendInt(nil, v, 10), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false
+		}
+		return strconv.AppendFloat(nil, v, 'f', -1, 64), true
+	default:
+		return nil, false
+	}
+}
+
+// appendStatsDName appends in to dst, replacing the characters that delimit the parts of a StatsD line with
+// underscores.
+func appendStatsDName(dst []byte, in string) []byte {
+	return appendReplacing(dst, in, ":|@#,\n")
+}
+
+// appendDogStatsDTag appends in to dst, replacing the characters that delimit DogStatsD tags with underscores.
+func appendDogStatsDTag(dst []byte, in string) []byte {
+	return appendReplacing(dst, in, "|#,\n")
+}
+
+func appendReplacing(dst []byte, in string, chars string) []byte {
+	for i := 0; i < len(in); i++ {
+		if strings.IndexByte(chars, in[i]) >= 0 {
+			dst = append(dst, '_')
+		} else {
+			dst = append(dst, in[i])
+		}
+	}
+	return dst
+}
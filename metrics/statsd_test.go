@@ -0,0 +1,159 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsDEmitter_DogStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	emitter, err := metrics.NewStatsDEmitter("udp", conn.LocalAddr().String(),
+		metrics.StatsDWithFormat(metrics.StatsDFormatDogStatsD),
+		metrics.StatsDPrefix("myapp"),
+	)
+	require.NoError(t, err)
+	defer func() {
+		_ = emitter.Close()
+	}()
+
+	root := metrics.NewRootMetricsRegistry()
+	root.Counter("requests", metrics.MustNewTag("endpoint", "get-foo"), metrics.MustNewTag("status", "2xx")).Inc(3)
+	root.Gauge("queue.size").Update(7)
+	root.GaugeFloat64("temperature").Update(-1.5)
+	root.Histogram("size").Update(5)
+
+	require.NoError(t, emitter.Emit(root))
+	assert.Equal(t, []string{
+		"myapp.queue.size:7|g",
+		"myapp.requests:3|c|#endpoint:get-foo,status:2xx",
+		"myapp.size.count:1|g",
+		"myapp.size.min:5|g",
+		"myapp.size.max:5|g",
+		"myapp.size.mean:5|g",
+		"myapp.size.stddev:0|g",
+		"myapp.size.p50:5|g",
+		"myapp.size.p95:5|g",
+		"myapp.size.p99:5|g",
+		"myapp.temperature:0|g",
+		"myapp.temperature:-1.5|g",
+	}, readStatsDLines(t, conn))
+
+	// counters are sent as the change since the previous emission
+	root.Counter("requests", metrics.MustNewTag("endpoint", "get-foo"), metrics.MustNewTag("status", "2xx")).Inc(2)
+	require.NoError(t, emitter.Emit(root))
+	assert.Equal(t, "myapp.requests:2|c|#endpoint:get-foo,status:2xx", readStatsDLines(t, conn)[1])
+}
+
+func TestStatsDEmitter_StatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	emitter, err := metrics.NewStatsDEmitter("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer func() {
+		_ = emitter.Close()
+	}()
+
+	root := metrics.NewRootMetricsRegistry()
+	root.Counter("requests", metrics.MustNewTag("status", "2xx")).Inc(3)
+	root.Gauge("queue:size").Update(7)
+
+	require.NoError(t, emitter.Emit(root))
+	assert.Equal(t, []string{
+		"queue_size:7|g",
+		"requests.status.2xx:3|c",
+	}, readStatsDLines(t, conn))
+}
+
+func TestStatsDEmitter_BatchesPackets(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	emitter, err := metrics.NewStatsDEmitter("udp", conn.LocalAddr().String(), metrics.StatsDMaxPacketSize(20))
+	require.NoError(t, err)
+	defer func() {
+		_ = emitter.Close()
+	}()
+
+	root := metrics.NewRootMetricsRegistry()
+	root.Gauge("a").Update(1)
+	root.Gauge("b").Update(2)
+	root.Gauge("c").Update(3)
+	root.Gauge("a-very-long-gauge-name").Update(4)
+
+	// metrics are visited in sorted order of name, so the long line separates "a" from "b" and "c"
+	require.NoError(t, emitter.Emit(root))
+	assert.Equal(t, []string{
+		"a:1|g",
+		"a-very-long-gauge-name:4|g",
+		"b:2|g\nc:3|g",
+	}, readStatsDPackets(t, conn, 3))
+}
+
+func TestStatsDEmitter_UnixDatagram(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "statsd.sock")
+	conn, err := net.ListenPacket("unixgram", socketPath)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	emitter, err := metrics.NewStatsDEmitter("unixgram", socketPath)
+	require.NoError(t, err)
+	defer func() {
+		_ = emitter.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	root := metrics.NewRootMetricsRegistry()
+	root.Gauge("queue.size").Update(7)
+	go emitter.Run(ctx, root, time.Millisecond)
+
+	assert.Equal(t, []string{"queue.size:7|g"}, readStatsDLines(t, conn))
+}
+
+func TestNewStatsDEmitter_UnsupportedNetwork(t *testing.T) {
+	_, err := metrics.NewStatsDEmitter("tcp", "127.0.0.1:8125")
+	assert.EqualError(t, err, `unsupported network "tcp": must be one of udp, udp4, udp6 or unixgram`)
+}
+
+// readStatsDLines reads a single packet and returns its lines.
+func readStatsDLines(t *testing.T, conn net.PacketConn) []string {
+	return strings.Split(readStatsDPackets(t, conn, 1)[0], "\n")
+}
+
+func readStatsDPackets(t *testing.T, conn net.PacketConn, n int) []string {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var packets []string
+	buf := make([]byte, 65536)
+	for i := 0; i < n; i++ {
+		read, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		packets = append(packets, string(buf[:read]))
+	}
+	return packets
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/palantir/go-metrics"
+)
+
+var (
+	// tagSetInterner stores every tagSet created by NewTagSet keyed by its id.
+	tagSetInterner      = make(map[string]*tagSet)
+	tagSetInternerMutex sync.RWMutex
+
+	emptyTagSet = NewTagSet()
+
+	_ Registry = &tagSetRegistry{}
+)
+
+// TagSet is an immutable, sorted set of tags. TagSets are interned: any two TagSets that contain the same tags are
+// backed by the same value and compare as equal using ==. Like Tags, a TagSet does not de-duplicate tags that have the
+// same key.
+//
+// Registering metrics on a registry returned by WithTagSet or obtained from a context created by AddTagSet does not
+// sort tags or build the metric's identifier again once the metric has been registered, so getting an existing metric
+// does not allocate. Because TagSets are retained for the lifetime of the process, they should only be created for
+// sets of tags with bounded cardinality. If the registry or context already has other tags, the identifiers are cached
+// on the returned registry rather than on the TagSet, so lookups only avoid allocating once the registry has been used.
+//
+// The zero value is the empty TagSet.
+type TagSet struct {
+	s *tagSet
+}
+
+type tagSet struct {
+	// sorted tags in the set. Must not be modified.
+	tags Tags
+	// id is the suffix appended to a metric name to create its metricTagsID: "|<tag1>|<tag2>".
+	id string
+
+	// cache of metric name to the metricTagsID for the metric with the name and this set's tags.
+	metricIDs      map[string]metricTagsID
+	metricIDsMutex sync.RWMutex
+}
+
+// NewTagSet returns the TagSet that contains the provided tags.
+func NewTagSet(tags ...Tag) TagSet {
+	s := newTagSet(tags)
+
+	tagSetInternerMutex.RLock()
+	interned, ok := tagSetInterner[s.id]
+	tagSetInternerMutex.RUnlock()
+	if ok {
+		return TagSet{s: interned}
+	}
+
+	tagSetInternerMutex.Lock()
+	defer tagSetInternerMutex.Unlock()
+	if interned, ok := tagSetInterner[s.id]; ok {
+		return TagSet{s: interned}
+	}
+	tagSetInterner[s.id] = s
+	return TagSet{s: s}
+}
+
+// newTagSet returns a tagSet that contains the provided tags without interning it, so that it is garbage collected
+// once it is no longer referenced.
+func newTagSet(tags Tags) *tagSet {
+	sortedTags := newSortedTags(tags)
+	return &tagSet{
+		tags:      sortedTags,
+		id:        string(toMetricTagsID("", sortedTags)),
+		metricIDs: make(map[string]metricTagsID),
+	}
+}
+
+// With returns the TagSet that contains the tags in this set and the provided tags.
+func (t TagSet) With(tags ...Tag) TagSet {
+	if len(tags) == 0 {
+		return t
+	}
+	current := t.set().tags
+	allTags := make(Tags, 0, len(current)+len(tags))
+	allTags = append(allTags, current...)
+	allTags = append(allTags, tags...)
+	return NewTagSet(allTags...)
+}
+
+// withoutInterning returns a TagSet that contains the tags in this set and the provided tags. Unlike With, the returned
+// set is not interned, so it should be used when the provided tags may have unbounded cardinality.
+func (t TagSet) withoutInterning(tags ...Tag) TagSet {
+	if len(tags) == 0 {
+		return t
+	}
+	current := t.set().tags
+	allTags := make(Tags, 0, len(current)+len(tags))
+	allTags = append(allTags, current...)
+	allTags = append(allTags, tags...)
+	return TagSet{s: newTagSet(allTags)}
+}
+
+// Tags returns a copy of the tags in the set in sorted order.
+func (t TagSet) Tags() Tags {
+	return newSortedTags(t.set().tags)
+}
+
+// Len returns the number of tags in the set.
+func (t TagSet) Len() int {
+	return len(t.set().tags)
+}
+
+// String returns the tags in the set in sorted order, separated by commas.
+func (t TagSet) String() string {
+	return strings.ReplaceAll(strings.TrimPrefix(t.set().id, "|"), "|", ",")
+}
+
+func (t TagSet) set() *tagSet {
+	if t.s == nil {
+		return emptyTagSet.s
+	}
+	return t.s
+}
+
+// metricID returns the metricTagsID for the metric with the provided name and the tags in this set.
+func (s *tagSet) metricID(name string) metricTagsID {
+	s.metricIDsMutex.RLock()
+	id, ok := s.metricIDs[name]
+	s.metricIDsMutex.RUnlock()
+	if ok {
+		return id
+	}
+	id = metricTagsID(name + s.id)
+	s.metricIDsMutex.Lock()
+	s.metricIDs[name] = id
+	s.metricIDsMutex.Unlock()
+	return id
+}
+
+// WithTagSet returns a registry that adds the tags in the provided TagSet to every metric registered on the provided
+// registry. Metrics registered on the returned registry without additional tags are looked up without allocating,
+// except on subregistries created with a non-empty prefix: the returned registry adds the tags to such a subregistry,
+// but the prefixed metric name is built on every lookup as it is for the subregistry. Registries that were not created
+// by this package are returned unchanged (consistent with the behavior of FromContext).
+//
+// Only the provided TagSet is interned: the tags of the provided registry are combined with it without creating a new
+// interned TagSet, since they may have been added to a context using AddTags with unbounded cardinality.
+func WithTagSet(registry Registry, tags TagSet) Registry {
+	switch r := registry.(type) {
+	case *rootRegistry:
+		return &tagSetRegistry{root: r, tags: tags}
+	case *tagSetRegistry:
+		return &tagSetRegistry{root: r.root, tags: tags.withoutInterning(r.tags.set().tags...)}
+	case *childRegistry:
+		if r.prefix == "" {
+			return &tagSetRegistry{root: r.root, tags: tags.withoutInterning(r.tags...)}
+		}
+		return &childRegistry{
+			prefix: r.prefix,
+			tags:   append(append(Tags(nil), r.tags...), tags.set().tags...),
+			root:   r.root,
+		}
+	default:
+		return registry
+	}
+}
+
+// AddTagSet adds the tags in the provided TagSet to the provided context in the same manner as AddTags. The registry
+// returned by FromContext for the returned context is created once by this function rather than on every call to
+// FromContext and supports the allocation-free lookups described for WithTagSet.
+//
+// Only the provided TagSet is interned: if the context already has tags, they are combined with it without creating a
+// new interned TagSet, so tags added using AddTags may have unbounded cardinality (for example, a request ID).
+func AddTagSet(ctx context.Context, tags TagSet) context.Context {
+	if tags.Len() == 0 {
+		return ctx
+	}
+	container, ok := ctx.Value(registryKey).(*registryContainer)
+	if !ok || container == nil {
+		container = &registryContainer{
+			Registry: DefaultMetricsRegistry,
+		}
+	}
+	newTags := make(Tags, 0, len(container.Tags)+tags.Len())
+	newTags = append(newTags, container.Tags...)
+	newTags = append(newTags, tags.set().tags...)
+	return context.WithValue(ctx, registryKey, newRegistryContainerWithTagSet(container.Registry, newTags, tags.withoutInterning(container.Tags...)))
+}
+
+// newRegistryContainerWithTagSet returns a container for the provided registry and tags. set must contain the same
+// tags as tags.
+func newRegistryContainerWithTagSet(registry Registry, tags Tags, set TagSet) *registryContainer {
+	container := &registryContainer{
+		Registry: registry,
+		Tags:     tags,
+	}
+	if root, ok := registry.(*rootRegistry); ok {
+		container.tagSetRegistry = &tagSetRegistry{root: root, tags: set}
+	}
+	return container
+}
+
+// tagSetRegistry is a child registry of a rootRegistry that adds the tags in a TagSet to all of its metrics.
+type tagSetRegistry struct {
+	root *rootRegistry
+	tags TagSet
+}
+
+func (r *tagSetRegistry) Counter(name string, tags ...Tag) metrics.Counter {
+	if len(tags) > 0 {
+		return r.root.Counter(name, r.withTags(tags)...)
+	}
+	if !r.root.conformsToSchema(name, MetricTypeCounter, r.tags.set().tags) {
+		return metrics.NilCounter{}
+	}
+	return metrics.GetOrRegisterCounter(r.root.registerMetricWithTagSet(name, r.tags), r.root.registry)
+}
+
+func (r *tagSetRegistry) Gauge(name string, tags ...Tag) metrics.Gauge {
+	if len(tags) > 0 {
+		return r.root.Gauge(name, r.withTags(tags)...)
+	}
+	if !r.root.conformsToSchema(name, MetricTypeGauge, r.tags.set().tags) {
+		return metrics.NilGauge{}
+	}
+	return metrics.GetOrRegisterGauge(r.root.registerMetricWithTagSet(name, r.tags), r.root.registry)
+}
+
+func (r *tagSetRegistry) GaugeFloat64(name string, tags ...Tag) metrics.GaugeFloat64 {
+	if len(tags) > 0 {
+		return r.root.GaugeFloat64(name, r.withTags(tags)...)
+	}
+	if !r.root.conformsToSchema(name, MetricTypeGauge, r.tags.set().tags) {
+		return metrics.NilGaugeFloat64{}
+	}
+	return metrics.GetOrRegisterGaugeFloat64(r.root.registerMetricWithTagSet(name, r.tags), r.root.registry)
+}
+
+func (r *tagSetRegistry) Meter(name string, tags ...Tag) metrics.Meter {
+	if len(tags) > 0 {
+		return r.root.Meter(name, r.withTags(tags)...)
+	}
+	if !r.root.conformsToSchema(name, MetricTypeMeter, r.tags.set().tags) {
+		return metrics.NilMeter{}
+	}
+	return metrics.GetOrRegisterMeter(r.root.registerMetricWithTagSet(name, r.tags), r.root.registry)
+}
+
+func (r *tagSetRegistry) Timer(name string, tags ...Tag) metrics.Timer {
+	if len(tags) > 0 {
+		return r.root.Timer(name, r.withTags(tags)...)
+	}
+	if !r.root.conformsToSchema(name, MetricTypeTimer, r.tags.set().tags) {
+		return metrics.NilTimer{}
+	}
+	return getOrRegisterMicroSecondsTimer(r.root.registerMetricWithTagSet(name, r.tags), r.root.registry)
+}
+
+func (r *tagSetRegistry) Histogram(name string, tags ...Tag) metrics.Histogram {
+	if len(tags) > 0 {
+		return r.root.Histogram(name, r.withTags(tags)...)
+	}
+	if !r.root.conformsToSchema(name, MetricTypeHistogram, r.tags.set().tags) {
+		return metrics.NilHistogram{}
+	}
+	return getOrRegisterHistogram(r.root.registerMetricWithTagSet(name, r.tags), r.root.registry)
+}
+
+func (r *tagSetRegistry) HistogramWithSample(name string, sample metrics.Sample, tags ...Tag) metrics.Histogram {
+	if len(tags) > 0 {
+		return r.root.HistogramWithSample(name, sample, r.withTags(tags)...)
+	}
+	if !r.root.conformsToSchema(name, MetricTypeHistogram, r.tags.set().tags) {
+		return metrics.NilHistogram{}
+	}
+	return metrics.GetOrRegisterHistogram(r.root.registerMetricWithTagSet(name, r.tags), r.root.registry, sample)
+}
+
+func (r *tagSetRegistry) Each(f MetricVisitor) {
+	r.root.Each(f)
+}
+
+func (r *tagSetRegistry) Unregister(name string, tags ...Tag) {
+	r.root.Unregister(name, r.withTags(tags)...)
+}
+
+func (r *tagSetRegistry) withTags(tags Tags) Tags {
+	setTags := r.tags.set().tags
+	allTags := make(Tags, 0, len(setTags)+len(tags))
+	allTags = append(allTags, setTags...)
+	return append(allTags, tags...)
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTagSet_DoesNotInternContextTags(t *testing.T) {
+	root := NewRootMetricsRegistry()
+	tags := NewTagSet(MustNewTag("app", "db"))
+
+	tagSetInternerMutex.RLock()
+	internedBefore := len(tagSetInterner)
+	tagSetInternerMutex.RUnlock()
+
+	for i := 0; i < 100; i++ {
+		ctx := AddTags(WithRegistry(context.Background(), root), MustNewTag("request", strconv.Itoa(i)))
+		ctx = AddTagSet(ctx, tags)
+		FromContext(WithRegistry(ctx, root)).Counter("my-counter").Inc(1)
+		WithTagSet(FromContext(ctx), tags).Counter("my-counter").Inc(1)
+	}
+
+	tagSetInternerMutex.RLock()
+	defer tagSetInternerMutex.RUnlock()
+	assert.Equal(t, internedBefore, len(tagSetInterner))
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTagSet(t *testing.T) {
+	a := metrics.MustNewTag("a", "1")
+	b := metrics.MustNewTag("b", "2")
+
+	ts := metrics.NewTagSet(b, a)
+	assert.Equal(t, metrics.Tags{a, b}, ts.Tags())
+	assert.Equal(t, 2, ts.Len())
+	assert.Equal(t, "a:1,b:2", ts.String())
+
+	// TagSets with the same tags are interned
+	assert.True(t, ts == metrics.NewTagSet(a, b))
+	assert.True(t, ts == metrics.NewTagSet(a).With(b))
+	assert.False(t, ts == metrics.NewTagSet(a))
+
+	// zero value is the empty set
+	assert.True(t, metrics.TagSet{}.With(a) == metrics.NewTagSet(a))
+	assert.Equal(t, 0, metrics.TagSet{}.Len())
+
+	// modifying the returned tags does not modify the set
+	tags := ts.Tags()
+	tags[0] = b
+	assert.Equal(t, metrics.Tags{a, b}, ts.Tags())
+}
+
+func TestWithTagSet(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	ts := metrics.NewTagSet(metrics.MustNewTag("region", "nw"))
+
+	reg := metrics.WithTagSet(root, ts)
+	reg.Counter("my-counter").Inc(1)
+	reg.Counter("my-counter").Inc(1)
+	reg.Counter("my-counter", metrics.MustNewTag("app", "db")).Inc(1)
+	metrics.WithTagSet(reg, metrics.NewTagSet(metrics.MustNewTag("app", "db"))).Counter("my-counter").Inc(1)
+
+	// metrics registered with a TagSet are the same metrics as those registered with the equivalent tags
+	assert.Equal(t, int64(2), root.Counter("my-counter", metrics.MustNewTag("region", "nw")).Count())
+	assert.Equal(t, int64(2), root.Counter("my-counter", metrics.MustNewTag("region", "nw"), metrics.MustNewTag("app", "db")).Count())
+
+	var gotTags []metrics.Tags
+	root.Each(func(name string, tags metrics.Tags, value metrics.MetricVal) {
+		gotTags = append(gotTags, tags)
+	})
+	assert.Equal(t, []metrics.Tags{
+		{metrics.MustNewTag("app", "db"), metrics.MustNewTag("region", "nw")},
+		{metrics.MustNewTag("region", "nw")},
+	}, gotTags)
+
+	reg.Unregister("my-counter")
+	assert.Equal(t, int64(0), reg.Counter("my-counter").Count())
+}
+
+func TestWithTagSet_PrefixedSubregistry(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	sub := root.Subregistry("prefix.", metrics.MustNewTag("app", "db"))
+
+	metrics.WithTagSet(sub, metrics.NewTagSet(metrics.MustNewTag("region", "nw"))).Counter("my-counter").Inc(1)
+	assert.Equal(t, int64(1), root.Counter("prefix.my-counter", metrics.MustNewTag("app", "db"), metrics.MustNewTag("region", "nw")).Count())
+}
+
+func TestAddTagSet(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	ctx := metrics.WithRegistry(context.Background(), root)
+	ctx = metrics.AddTags(ctx, metrics.MustNewTag("region", "nw"))
+	ctx = metrics.AddTagSet(ctx, metrics.NewTagSet(metrics.MustNewTag("app", "db")))
+
+	assert.Equal(t, metrics.Tags{metrics.MustNewTag("region", "nw"), metrics.MustNewTag("app", "db")}, metrics.TagsFromContext(ctx))
+	metrics.FromContext(ctx).Counter("my-counter").Inc(1)
+	assert.Equal(t, int64(1), root.Counter("my-counter", metrics.MustNewTag("app", "db"), metrics.MustNewTag("region", "nw")).Count())
+
+	// replacing the registry retains the tags
+	otherRoot := metrics.NewRootMetricsRegistry()
+	metrics.FromContext(metrics.WithRegistry(ctx, otherRoot)).Counter("my-counter").Inc(1)
+	assert.Equal(t, int64(1), otherRoot.Counter("my-counter", metrics.MustNewTag("app", "db"), metrics.MustNewTag("region", "nw")).Count())
+}
+
+func TestTagSetLookupDoesNotAllocate(t *testing.T) {
+	root := metrics.NewRootMetricsRegistry()
+	ctx := metrics.AddTagSet(metrics.WithRegistry(context.Background(), root), metrics.NewTagSet(metrics.MustNewTag("region", "nw")))
+	metrics.FromContext(ctx).Counter("my-counter").Inc(1)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		metrics.FromContext(ctx).Counter("my-counter").Inc(1)
+	})
+	require.Equal(t, float64(0), allocs)
+}
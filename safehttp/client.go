@@ -5,16 +5,18 @@
 package safehttp
 
 import (
+	"context"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // Client is a type alias for http.Client that redefines the request functions (Get, Head, Post, PostForm, Do) to return
 // an additional cleanup function. The returned cleanup function should be deferred after the call is made, and ensures
-// that the response body is fully drained and closed so that subsequent calls that are made using the same client will
-// properly reuse connections.
+// that the response body is drained and closed so that subsequent calls that are made using the same client will
+// properly reuse connections. At most MaxDrainBytes are drained: the connection of a response with a larger unread body
+// is closed rather than reused.
 type Client http.Client
 
 func (c *Client) Get(url string) (resp *http.Response, cleanup func(), err error) {
@@ -37,6 +39,39 @@ func (c *Client) PostForm(url string, data url.Values) (resp *http.Response, cle
 	return resp, responseCloser(resp), err
 }
 
+// GetContext is like Get but sends the request with the provided context.
+func (c *Client) GetContext(ctx context.Context, url string) (resp *http.Response, cleanup func(), err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, responseCloser(nil), err
+	}
+	return c.Do(req)
+}
+
+// HeadContext is like Head but sends the request with the provided context.
+func (c *Client) HeadContext(ctx context.Context, url string) (resp *http.Response, cleanup func(), err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, responseCloser(nil), err
+	}
+	return c.Do(req)
+}
+
+// PostContext is like Post but sends the request with the provided context.
+func (c *Client) PostContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, cleanup func(), err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, responseCloser(nil), err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// PostFormContext is like PostForm but sends the request with the provided context.
+func (c *Client) PostFormContext(ctx context.Context, url string, data url.Values) (resp *http.Response, cleanup func(), err error) {
+	return c.PostContext(ctx, url, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+}
+
 func (c *Client) Do(req *http.Request) (resp *http.Response, cleanup func(), err error) {
 	resp, err = (*http.Client)(c).Do(req)
 	return resp, responseCloser(resp), err
@@ -48,8 +83,6 @@ func responseCloser(resp *http.Response) func() {
 		return func() {}
 	}
 	return func() {
-		// drain and close treated as best-effort and errors are not reported
-		_, _ = io.Copy(ioutil.Discard, resp.Body)
-		_ = resp.Body.Close()
+		DrainAndClose(resp)
 	}
 }
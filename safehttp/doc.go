@@ -12,4 +12,8 @@
 // in a manner that also returns a cleanup function that drains and closes the body of the response. Callers can simply
 // defer the returned cleanup function to ensure that the connections are properly relinquished. It is safe for the
 // cleanup function to execute even if the body has already been drained or closed.
+//
+// Response bodies are drained up to MaxDrainBytes, so that cleaning up a response with a very large body does not block
+// on reading it. CheckStatus and DecodeJSON replace hand-written status checks and decoding: responses that are not 2xx
+// are reported as a *StatusError that includes the status code and the beginning of the body.
 package safehttp
@@ -5,6 +5,7 @@
 package safehttp
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/url"
@@ -29,3 +30,23 @@ func PostForm(c *http.Client, url string, data url.Values) (resp *http.Response,
 func Do(c *http.Client, req *http.Request) (resp *http.Response, cleanup func(), err error) {
 	return (*Client)(c).Do(req)
 }
+
+// GetContext is like Get but sends the request with the provided context.
+func GetContext(ctx context.Context, c *http.Client, url string) (resp *http.Response, cleanup func(), err error) {
+	return (*Client)(c).GetContext(ctx, url)
+}
+
+// HeadContext is like Head but sends the request with the provided context.
+func HeadContext(ctx context.Context, c *http.Client, url string) (resp *http.Response, cleanup func(), err error) {
+	return (*Client)(c).HeadContext(ctx, url)
+}
+
+// PostContext is like Post but sends the request with the provided context.
+func PostContext(ctx context.Context, c *http.Client, url string, contentType string, body io.Reader) (resp *http.Response, cleanup func(), err error) {
+	return (*Client)(c).PostContext(ctx, url, contentType, body)
+}
+
+// PostFormContext is like PostForm but sends the request with the provided context.
+func PostFormContext(ctx context.Context, c *http.Client, url string, data url.Values) (resp *http.Response, cleanup func(), err error) {
+	return (*Client)(c).PostFormContext(ctx, url, data)
+}
@@ -4,6 +4,7 @@ go 1.25.0
 
 require (
 	github.com/palantir/pkg v1.1.0
+	github.com/palantir/pkg/safejson v1.2.0
 	github.com/stretchr/testify v1.11.1
 )
 
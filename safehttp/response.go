@@ -0,0 +1,166 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/palantir/pkg/safejson"
+)
+
+const (
+	// MaxDrainBytes is the maximum number of bytes read from the body of a response by DrainAndClose and by the cleanup
+	// functions returned by Client. Draining a larger body is likely to take longer than establishing a new
+	// connection, so the connection of such a response is closed instead.
+	MaxDrainBytes = 64 << 10
+
+	// MaxErrorBodyBytes is the maximum number of bytes of the body of a response included in a StatusError.
+	MaxErrorBodyBytes = 1 << 10
+
+	// DefaultMaxBodyBytes is the default maximum size of a body decoded by DecodeJSON.
+	DefaultMaxBodyBytes = 10 << 20
+)
+
+// DrainAndClose reads at most MaxDrainBytes from the body of the response and closes it, so that its connection can be
+// reused. Errors are not reported, since draining and closing is best-effort. It is safe to call DrainAndClose with a
+// nil response or with a response whose body has already been drained or closed.
+func DrainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, MaxDrainBytes)
+	_ = resp.Body.Close()
+}
+
+// StatusError is returned by CheckStatus and DecodeJSON for responses with a status code that is not 2xx.
+type StatusError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Status is the status of the response, such as "404 Not Found".
+	Status string
+	// Body contains at most MaxErrorBodyBytes of the body of the response.
+	Body string
+	// Truncated is true if the body of the response was longer than Body.
+	Truncated bool
+}
+
+func (e *StatusError) Error() string {
+	msg := "unexpected response status " + e.Status
+	if e.Body == "" {
+		return msg
+	}
+	body := e.Body
+	if e.Truncated {
+		body += "..."
+	}
+	return msg + ": " + body
+}
+
+// CheckStatus returns a *StatusError if the status code of the response is not 2xx, or nil otherwise. The body of a
+// response with an unexpected status is partially read in order to populate the error, but is not closed. Returns an
+// error if the response is nil.
+func CheckStatus(resp *http.Response) error {
+	if resp == nil {
+		return errors.New("response is nil")
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err := &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodyBytes+1))
+		if len(body) > MaxErrorBodyBytes {
+			body = body[:MaxErrorBodyBytes]
+			err.Truncated = true
+		}
+		err.Body = strings.TrimSpace(string(body))
+	}
+	return err
+}
+
+// DecodeParam configures DecodeJSON.
+type DecodeParam func(*decodeOptions)
+
+type decodeOptions struct {
+	maxBodyBytes     int64
+	checkContentType bool
+}
+
+// MaxBodyBytes configures the maximum size of the body decoded by DecodeJSON. The default is DefaultMaxBodyBytes.
+func MaxBodyBytes(n int64) DecodeParam {
+	return func(o *decodeOptions) {
+		o.maxBodyBytes = n
+	}
+}
+
+// SkipContentTypeCheck configures DecodeJSON to decode the body regardless of the Content-Type of the response.
+func SkipContentTypeCheck() DecodeParam {
+	return func(o *decodeOptions) {
+		o.checkContentType = false
+	}
+}
+
+// DecodeJSON decodes the JSON body of the response into a value of type T using safejson. Returns a *StatusError if
+// the status code of the response is not 2xx, and an error if the Content-Type of the response is not
+// "application/json" or a "+json" type, if the body is larger than the maximum size or if the response is nil. The body
+// of the response is drained and closed before DecodeJSON returns.
+func DecodeJSON[T any](resp *http.Response, params ...DecodeParam) (T, error) {
+	defer DrainAndClose(resp)
+	var result T
+	opts := &decodeOptions{
+		maxBodyBytes:     DefaultMaxBodyBytes,
+		checkContentType: true,
+	}
+	for _, param := range params {
+		if param != nil {
+			param(opts)
+		}
+	}
+
+	if err := CheckStatus(resp); err != nil {
+		return result, err
+	}
+	if opts.checkContentType {
+		if err := checkJSONContentType(resp.Header.Get("Content-Type")); err != nil {
+			return result, err
+		}
+	}
+	if resp.Body == nil {
+		return result, errors.New("response does not have a body")
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, opts.maxBodyBytes+1))
+	if err != nil {
+		return result, fmt.Errorf("failed to read response body: %v", err)
+	}
+	if int64(len(body)) > opts.maxBodyBytes {
+		return result, fmt.Errorf("response body exceeds maximum size of %d bytes", opts.maxBodyBytes)
+	}
+	if err := safejson.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("failed to decode JSON response body: %v", err)
+	}
+	return result, nil
+}
+
+func checkJSONContentType(contentType string) error {
+	if contentType == "" {
+		return fmt.Errorf("response does not have a Content-Type, expected application/json")
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid response Content-Type %q: %v", contentType, err)
+	}
+	if mediaType != "application/json" && !(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")) {
+		return fmt.Errorf("unexpected response Content-Type %q, expected application/json", contentType)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safehttp_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/palantir/pkg/safehttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingReader struct {
+	read   int64
+	closed bool
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func (r *countingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestDrainAndCloseIsBounded(t *testing.T) {
+	body := &countingReader{}
+	safehttp.DrainAndClose(&http.Response{Body: body})
+	assert.True(t, body.closed)
+	assert.Equal(t, int64(safehttp.MaxDrainBytes), body.read)
+
+	safehttp.DrainAndClose(nil)
+	safehttp.DrainAndClose(&http.Response{})
+}
+
+func TestCheckStatus(t *testing.T) {
+	assert.NoError(t, safehttp.CheckStatus(&http.Response{StatusCode: http.StatusNoContent}))
+
+	err := safehttp.CheckStatus(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Body:       io.NopCloser(strings.NewReader("no such thing\n")),
+	})
+	var statusErr *safehttp.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.EqualError(t, err, "unexpected response status 404 Not Found: no such thing")
+
+	err = safehttp.CheckStatus(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Status:     "500 Internal Server Error",
+		Body:       io.NopCloser(strings.NewReader(strings.Repeat("x", 2*safehttp.MaxErrorBodyBytes))),
+	})
+	require.True(t, errors.As(err, &statusErr))
+	assert.True(t, statusErr.Truncated)
+	assert.Len(t, statusErr.Body, safehttp.MaxErrorBodyBytes)
+	assert.True(t, strings.HasSuffix(err.Error(), "x..."))
+}
+
+func TestDecodeJSON(t *testing.T) {
+	type result struct {
+		Name string `json:"name"`
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = io.WriteString(w, `{"name":"foo"}`)
+		case "/problem":
+			w.Header().Set("Content-Type", "application/problem+json")
+			_, _ = io.WriteString(w, `{"name":"problem"}`)
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, `{"name":"text"}`)
+		case "/large":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"name":"`+strings.Repeat("x", 100)+`"}`)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	get := func(path string) *http.Response {
+		resp, _, err := safehttp.GetContext(context.Background(), ts.Client(), ts.URL+path)
+		require.NoError(t, err)
+		return resp
+	}
+
+	got, err := safehttp.DecodeJSON[result](get("/json"))
+	require.NoError(t, err)
+	assert.Equal(t, result{Name: "foo"}, got)
+
+	got, err = safehttp.DecodeJSON[result](get("/problem"))
+	require.NoError(t, err)
+	assert.Equal(t, result{Name: "problem"}, got)
+
+	_, err = safehttp.DecodeJSON[result](get("/text"))
+	assert.EqualError(t, err, `unexpected response Content-Type "text/plain", expected application/json`)
+
+	got, err = safehttp.DecodeJSON[result](get("/text"), safehttp.SkipContentTypeCheck())
+	require.NoError(t, err)
+	assert.Equal(t, result{Name: "text"}, got)
+
+	_, err = safehttp.DecodeJSON[result](get("/large"), safehttp.MaxBodyBytes(64))
+	assert.EqualError(t, err, "response body exceeds maximum size of 64 bytes")
+
+	_, err = safehttp.DecodeJSON[map[string]interface{}](get("/missing"))
+	var statusErr *safehttp.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, "not found", statusErr.Body)
+}
+
+func TestNilResponse(t *testing.T) {
+	assert.EqualError(t, safehttp.CheckStatus(nil), "response is nil")
+	_, err := safehttp.DecodeJSON[map[string]string](nil)
+	assert.EqualError(t, err, "response is nil")
+	_, err = safehttp.DecodeJSON[map[string]string](&http.Response{StatusCode: http.StatusOK}, safehttp.SkipContentTypeCheck())
+	assert.EqualError(t, err, "response does not have a body")
+}
+
+func TestContextFunctions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		_, _ = io.WriteString(w, r.Method+" "+r.Header.Get("Content-Type")+" "+r.Form.Get("key"))
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	readBody := func(resp *http.Response, cleanup func(), err error) string {
+		require.NoError(t, err)
+		defer cleanup()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+	assert.Equal(t, "GET  ", readBody(safehttp.GetContext(ctx, ts.Client(), ts.URL)))
+	assert.Equal(t, "POST text/plain ", readBody(safehttp.PostContext(ctx, ts.Client(), ts.URL, "text/plain", strings.NewReader("body"))))
+	assert.Equal(t, "POST application/x-www-form-urlencoded value", readBody(safehttp.PostFormContext(ctx, ts.Client(), ts.URL, url.Values{"key": {"value"}})))
+
+	resp, cleanup, err := safehttp.HeadContext(ctx, ts.Client(), ts.URL)
+	require.NoError(t, err)
+	defer cleanup()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, cleanup, err = safehttp.GetContext(cancelled, ts.Client(), ts.URL)
+	defer cleanup()
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -4,6 +4,7 @@ go 1.25.0
 
 require (
 	github.com/palantir/pkg v1.1.0
+	github.com/palantir/pkg/transform v1.2.0
 	github.com/stretchr/testify v1.11.1
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -13,3 +14,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/palantir/pkg/transform => ../transform
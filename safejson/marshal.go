@@ -7,11 +7,22 @@ package safejson
 import (
 	"bytes"
 	"encoding/json"
+
+	"github.com/palantir/pkg/transform"
 )
 
 // Marshal returns the JSON encoding of v encoded using the "safe" encoder.
 // Unlike json.Marshal, the returned JSON bytes will not have a trailing newline.
-func Marshal(v interface{}) ([]byte, error) {
+func Marshal(v interface{}, opts ...MarshalOption) ([]byte, error) {
+	var options marshalOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.revealSecrets {
+		v = transform.RevealRedacted(v)
+	}
 	// go through Encoder to control SetEscapeHTML
 	var buf bytes.Buffer
 	if err := Encoder(&buf).Encode(v); err != nil {
@@ -21,8 +32,8 @@ func Marshal(v interface{}) ([]byte, error) {
 }
 
 // MarshalIndent is like Marshal but applies Indent to format the output.
-func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
-	b, err := Marshal(v)
+func MarshalIndent(v interface{}, prefix, indent string, opts ...MarshalOption) ([]byte, error) {
+	b, err := Marshal(v, opts...)
 	if err != nil {
 		return nil, err
 	}
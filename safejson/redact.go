@@ -0,0 +1,21 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safejson
+
+// MarshalOption configures Marshal and MarshalIndent.
+type MarshalOption func(*marshalOptions)
+
+type marshalOptions struct {
+	revealSecrets bool
+}
+
+// UnsafeRevealSecrets configures Marshal to marshal the contents of transform.Redactable values, such as
+// bearertoken.Secret, instead of redacting them. The output will contain secrets and must not be logged or otherwise
+// exposed. See transform.RevealRedacted for the values that are revealed.
+func UnsafeRevealSecrets() MarshalOption {
+	return func(o *marshalOptions) {
+		o.revealSecrets = true
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safejson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/palantir/pkg/safejson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSecret struct {
+	value    string
+	revealed bool
+}
+
+func (s testSecret) Unredacted() interface{} {
+	return testSecret{value: s.value, revealed: true}
+}
+
+func (s testSecret) MarshalJSON() ([]byte, error) {
+	if s.revealed {
+		return json.Marshal(s.value)
+	}
+	return json.Marshal("[REDACTED]")
+}
+
+func TestMarshalRevealSecrets(t *testing.T) {
+	type config struct {
+		Password    testSecret
+		Token       *testSecret
+		NilToken    *testSecret
+		Credentials map[string]interface{}
+		Keys        []testSecret
+	}
+	in := config{
+		Password:    testSecret{value: "password"},
+		Token:       &testSecret{value: "token"},
+		Credentials: map[string]interface{}{"key": testSecret{value: "key"}},
+		Keys:        []testSecret{{value: "key-1"}},
+	}
+
+	out, err := safejson.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"Password":"[REDACTED]","Token":"[REDACTED]","NilToken":null,"Credentials":{"key":"[REDACTED]"},"Keys":["[REDACTED]"]}`, string(out))
+
+	out, err = safejson.Marshal(in, safejson.UnsafeRevealSecrets())
+	require.NoError(t, err)
+	assert.Equal(t, `{"Password":"password","Token":"token","NilToken":null,"Credentials":{"key":"key"},"Keys":["key-1"]}`, string(out))
+	assert.False(t, in.Password.revealed || in.Token.revealed, "the input is not modified")
+
+	out, err = safejson.MarshalIndent(testSecret{value: "secret"}, "", "  ", safejson.UnsafeRevealSecrets())
+	require.NoError(t, err)
+	assert.Equal(t, `"secret"`, string(out))
+}
@@ -16,3 +16,5 @@ require (
 )
 
 replace gopkg.in/yaml.v2 => gopkg.in/yaml.v2 v2.2.1
+
+replace github.com/palantir/pkg/transform => ../transform
@@ -13,7 +13,16 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
-func Marshal(in interface{}) (out []byte, err error) {
+func Marshal(in interface{}, opts ...MarshalOption) (out []byte, err error) {
+	var options marshalOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.revealSecrets {
+		in = transform.RevealRedacted(in)
+	}
 	converted := numbersToPrimitives(in)
 	return yaml.Marshal(converted)
 }
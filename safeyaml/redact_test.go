@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safeyaml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSecret struct {
+	value    int
+	revealed bool
+}
+
+func (s testSecret) Unredacted() interface{} {
+	return testSecret{value: s.value, revealed: true}
+}
+
+func (s testSecret) MarshalYAML() (interface{}, error) {
+	if s.revealed {
+		return s.value, nil
+	}
+	return "[REDACTED]", nil
+}
+
+func TestMarshalRevealSecrets(t *testing.T) {
+	type config struct {
+		Pin    testSecret             `yaml:"pin"`
+		PinPtr *testSecret            `yaml:"pin-ptr"`
+		Keys   map[string]interface{} `yaml:"keys"`
+	}
+	in := config{
+		Pin:    testSecret{value: 1234},
+		PinPtr: &testSecret{value: 5678},
+		Keys:   map[string]interface{}{"a": testSecret{value: 1}},
+	}
+
+	out, err := Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, "pin: '[REDACTED]'\npin-ptr: '[REDACTED]'\nkeys:\n  a: '[REDACTED]'\n", string(out))
+
+	out, err = Marshal(in, UnsafeRevealSecrets())
+	require.NoError(t, err)
+	assert.Equal(t, "pin: 1234\npin-ptr: 5678\nkeys:\n  a: 1\n", string(out))
+}
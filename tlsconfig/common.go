@@ -144,6 +144,5 @@ func configureTLSConfig(tlsCfg *tls.Config, cfgs ...configurer) (*tls.Config, er
 			return nil, err
 		}
 	}
-	tlsCfg.BuildNameToCertificate()
 	return tlsCfg, nil
 }
@@ -4,11 +4,20 @@ go 1.25.0
 
 require (
 	github.com/palantir/pkg v1.1.0
+	github.com/palantir/pkg/metrics v1.9.0
+	github.com/palantir/pkg/refreshable/v2 v2.3.0
 	github.com/stretchr/testify v1.11.1
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78
+	software.sslmate.com/src/go-pkcs12 v0.7.3
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/palantir/go-metrics v1.1.1 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.22.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/palantir/pkg/refreshable/v2 => ../refreshable
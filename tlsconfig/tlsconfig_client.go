@@ -26,11 +26,16 @@ func NewClientConfig(params ...ClientParam) (*tls.Config, error) {
 
 // NewClientConfigWithBaseConfig is identical to NewClientConfig however a base tls.Config is allowed to be specified
 func NewClientConfigWithBaseConfig(b *tls.Config, params ...ClientParam) (*tls.Config, error) {
-	configurers := make([]configurer, len(params))
-	for i, p := range params {
-		configurers[i] = configurer(p.configureClient)
+	var configurers, verifyHostnameConfigurers []configurer
+	for _, p := range params {
+		if _, ok := p.(verifyHostnameParam); ok {
+			// configured last so that the server's certificate is verified against the final RootCAs
+			verifyHostnameConfigurers = append(verifyHostnameConfigurers, p.configureClient)
+			continue
+		}
+		configurers = append(configurers, p.configureClient)
 	}
-	return configureTLSConfig(b, configurers...)
+	return configureTLSConfig(b, append(configurers, verifyHostnameConfigurers...)...)
 }
 
 type ClientParam interface {
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ExpiryHandler is called by an ExpiryMonitor with the name of a monitored source and the earliest NotAfter time of the
+// certificates that it returned.
+type ExpiryHandler func(name string, notAfter time.Time)
+
+// ExpiryMonitor periodically loads certificates and reports when they expire to an ExpiryHandler. Each source of
+// certificates is added to the monitor with a name that is passed to the handler. The handler typically publishes the
+// time until expiry as a metric: tlsconfigmetrics.ExpiryDaysGauge returns a handler that publishes the number of days
+// until expiry as a gauge on a metrics registry, for example:
+//
+//	monitor := tlsconfig.NewExpiryMonitor(tlsconfigmetrics.ExpiryDaysGauge(registry))
+type ExpiryMonitor struct {
+	onExpiry ExpiryHandler
+
+	mutex   sync.Mutex
+	sources map[string]func() ([]*x509.Certificate, error)
+}
+
+// NewExpiryMonitor returns a monitor that reports the expiry of its sources to the provided handler.
+func NewExpiryMonitor(onExpiry ExpiryHandler) *ExpiryMonitor {
+	return &ExpiryMonitor{
+		onExpiry: onExpiry,
+		sources:  make(map[string]func() ([]*x509.Certificate, error)),
+	}
+}
+
+// AddTLSCert adds the certificate chain returned by the provided provider to the monitor with the provided name. The
+// provider is called every time the monitor is updated.
+func (m *ExpiryMonitor) AddTLSCert(name string, provider TLSCertProvider) {
+	m.addSource(name, func() ([]*x509.Certificate, error) {
+		cert, err := provider()
+		if err != nil {
+			return nil, err
+		}
+		return parseTLSCertificateChain(cert)
+	})
+}
+
+// AddCAFiles adds the certificates in the provided PEM-encoded CA files to the monitor with the provided name. The
+// files are read every time the monitor is updated.
+func (m *ExpiryMonitor) AddCAFiles(name string, caFiles ...string) {
+	m.addSource(name, func() ([]*x509.Certificate, error) {
+		return readPEMCertificateFiles(caFiles...)
+	})
+}
+
+// AddCertificates adds the certificates returned by the provided function to the monitor with the provided name. The
+// function is called every time the monitor is updated.
+func (m *ExpiryMonitor) AddCertificates(name string, certs func() ([]*x509.Certificate, error)) {
+	m.addSource(name, certs)
+}
+
+func (m *ExpiryMonitor) addSource(name string, source func() ([]*x509.Certificate, error)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sources[name] = source
+}
+
+// Update loads the certificates from every source and calls the handler of the monitor for each source that returned at
+// least one certificate, in sorted order of name. If a source fails to load, the handler is not called for it and the
+// error is included in the returned error.
+func (m *ExpiryMonitor) Update() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	names := make([]string, 0, len(m.sources))
+	for name := range m.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		certs, err := m.sources[name]()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load certificates for %s: %v", name, err))
+			continue
+		}
+		if len(certs) == 0 {
+			continue
+		}
+		earliest := certs[0].NotAfter
+		for _, cert := range certs[1:] {
+			if cert.NotAfter.Before(earliest) {
+				earliest = cert.NotAfter
+			}
+		}
+		m.onExpiry(name, earliest)
+	}
+	return errors.Join(errs...)
+}
+
+// Run calls Update every updateFrequency until ctx is done. Run blocks and should be started in its own goroutine.
+// Errors returned by Update are passed to onError if it is non-nil.
+func (m *ExpiryMonitor) Run(ctx context.Context, updateFrequency time.Duration, onError func(error)) {
+	t := time.NewTicker(updateFrequency)
+	defer t.Stop()
+	for {
+		if err := m.Update(); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+)
+
+// CertificateInfo is a summary of the fields of an X.509 certificate that are relevant when diagnosing TLS
+// configuration problems.
+type CertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serialNumber"`
+	DNSNames     []string  `json:"dnsNames,omitempty"`
+	IPAddresses  []string  `json:"ipAddresses,omitempty"`
+	URIs         []string  `json:"uris,omitempty"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	// KeyType is the type and size of the certificate's public key, for example "RSA-2048", "ECDSA-P-256" or "Ed25519".
+	KeyType string `json:"keyType"`
+	IsCA    bool   `json:"isCA"`
+}
+
+// InspectCertificate returns the CertificateInfo for the provided certificate.
+func InspectCertificate(cert *x509.Certificate) CertificateInfo {
+	info := CertificateInfo{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		DNSNames:     cert.DNSNames,
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		KeyType:      publicKeyType(cert.PublicKey),
+		IsCA:         cert.IsCA,
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		info.URIs = append(info.URIs, uri.String())
+	}
+	return info
+}
+
+// InspectTLSCertificate returns the CertificateInfo for each certificate in the chain of the provided tls.Certificate,
+// starting with the leaf certificate.
+func InspectTLSCertificate(cert tls.Certificate) ([]CertificateInfo, error) {
+	certs, err := parseTLSCertificateChain(cert)
+	if err != nil {
+		return nil, err
+	}
+	return inspectCertificates(certs), nil
+}
+
+// InspectPEM returns the CertificateInfo for each certificate in the provided PEM-encoded bytes. Blocks that are not
+// certificates, such as private keys, are ignored. Returns an error if the bytes do not contain any certificates.
+func InspectPEM(pemBytes []byte) ([]CertificateInfo, error) {
+	certs, err := parsePEMCertificates(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	return inspectCertificates(certs), nil
+}
+
+// InspectCAFiles returns the CertificateInfo for each certificate in the provided PEM-encoded CA files. Because an
+// x509.CertPool does not expose the certificates it contains, this function can be used to inspect the certificates
+// that CertPoolFromCAFiles adds to a pool.
+func InspectCAFiles(caFiles ...string) ([]CertificateInfo, error) {
+	certs, err := readPEMCertificateFiles(caFiles...)
+	if err != nil {
+		return nil, err
+	}
+	return inspectCertificates(certs), nil
+}
+
+// CheckExpiry returns an error if the certificate described by the CertificateInfo is expired or expires within the
+// provided window of now.
+func (c CertificateInfo) CheckExpiry(now time.Time, window time.Duration) error {
+	switch {
+	case now.After(c.NotAfter):
+		return fmt.Errorf("certificate %q expired at %s", c.Subject, c.NotAfter.UTC().Format(time.RFC3339))
+	case now.Add(window).After(c.NotAfter):
+		return fmt.Errorf("certificate %q expires at %s, which is within %s", c.Subject, c.NotAfter.UTC().Format(time.RFC3339), window)
+	default:
+		return nil
+	}
+}
+
+// TLSCertWithExpiryCheck returns a provider that returns the certificate returned by the provided provider, or an
+// error if any certificate in its chain is expired or expires within the provided window. Because NewServerConfig
+// loads the server's certificate when it is called, using this provider causes NewServerConfig to fail for a
+// certificate that is about to expire. Client certificates provided using ClientKeyPair are loaded on each handshake,
+// so the handshake fails instead.
+func TLSCertWithExpiryCheck(provider TLSCertProvider, window time.Duration) TLSCertProvider {
+	return func() (tls.Certificate, error) {
+		cert, err := provider()
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		infos, err := InspectTLSCertificate(cert)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		now := time.Now()
+		for _, info := range infos {
+			if err := info.CheckExpiry(now, window); err != nil {
+				return tls.Certificate{}, err
+			}
+		}
+		return cert, nil
+	}
+}
+
+// CertPoolOptionFromCAFilesWithExpiryCheck is identical to CertPoolOptionFromCAFiles, but returns an error if any of
+// the certificates in the files is expired or expires within the provided window.
+func CertPoolOptionFromCAFilesWithExpiryCheck(window time.Duration, caFiles ...string) CertPoolOption {
+	return func(certPool *x509.CertPool) error {
+		certs, err := readPEMCertificateFiles(caFiles...)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, cert := range certs {
+			if err := InspectCertificate(cert).CheckExpiry(now, window); err != nil {
+				return err
+			}
+		}
+		for _, cert := range certs {
+			certPool.AddCert(cert)
+		}
+		return nil
+	}
+}
+
+func inspectCertificates(certs []*x509.Certificate) []CertificateInfo {
+	infos := make([]CertificateInfo, len(certs))
+	for i, cert := range certs {
+		infos[i] = InspectCertificate(cert)
+	}
+	return infos
+}
+
+func parseTLSCertificateChain(cert tls.Certificate) ([]*x509.Certificate, error) {
+	if len(cert.Certificate) == 0 {
+		return nil, fmt.Errorf("no certificates found in TLS certificate")
+	}
+	certs := make([]*x509.Certificate, len(cert.Certificate))
+	for i, der := range cert.Certificate {
+		if i == 0 && cert.Leaf != nil {
+			certs[i] = cert.Leaf
+			continue
+		}
+		parsed, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %v", err)
+		}
+		certs[i] = parsed
+	}
+	return certs, nil
+}
+
+func parsePEMCertificates(pemBytes []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates detected")
+	}
+	return certs, nil
+}
+
+func readPEMCertificateFiles(files ...string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, file := range files {
+		pemBytes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificates from file %s: %v", file, err)
+		}
+		fileCerts, err := parsePEMCertificates(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificates from file %s: %v", file, err)
+		}
+		certs = append(certs, fileCerts...)
+	}
+	return certs, nil
+}
+
+func publicKeyType(publicKey interface{}) string {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA-%d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA-" + key.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("%T", publicKey)
+	}
+}
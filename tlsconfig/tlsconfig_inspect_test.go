@@ -0,0 +1,125 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectCAFiles(t *testing.T) {
+	infos, err := tlsconfig.InspectCAFiles(caCertFile)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "O=Root CA", infos[0].Subject)
+	assert.Equal(t, "O=Root CA", infos[0].Issuer)
+	assert.Equal(t, "RSA-2048", infos[0].KeyType)
+	assert.True(t, infos[0].IsCA)
+
+	_, err = tlsconfig.InspectCAFiles(serverKeyFile)
+	assert.EqualError(t, err, "failed to load certificates from file testdata/server-key.pem: no certificates detected")
+}
+
+func TestInspectPEM(t *testing.T) {
+	infos, err := tlsconfig.InspectPEM(readFile(t, serverCertFile))
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "CN=localhost,O=Test Org", infos[0].Subject)
+	assert.Equal(t, "O=Root CA", infos[0].Issuer)
+	assert.Equal(t, []string{"localhost"}, infos[0].DNSNames)
+	assert.Equal(t, []string{"127.0.0.1"}, infos[0].IPAddresses)
+	assert.False(t, infos[0].IsCA)
+}
+
+func TestInspectTLSCertificate(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "test"},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  notAfter,
+	}, true)
+
+	infos, err := tlsconfig.InspectTLSCertificate(cert)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "CN=test", infos[0].Subject)
+	assert.Equal(t, "ECDSA-P-256", infos[0].KeyType)
+	assert.True(t, notAfter.Equal(infos[0].NotAfter))
+	assert.Equal(t, "O=Root CA", infos[1].Subject)
+}
+
+func TestCertificateInfoCheckExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	info := tlsconfig.CertificateInfo{Subject: "CN=test", NotAfter: now.Add(48 * time.Hour)}
+
+	assert.NoError(t, info.CheckExpiry(now, 24*time.Hour))
+	assert.EqualError(t, info.CheckExpiry(now, 72*time.Hour), `certificate "CN=test" expires at 2026-01-03T00:00:00Z, which is within 72h0m0s`)
+	assert.EqualError(t, info.CheckExpiry(now.Add(72*time.Hour), 0), `certificate "CN=test" expired at 2026-01-03T00:00:00Z`)
+}
+
+func TestTLSCertWithExpiryCheck(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "expiring"},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	}, true)
+	provider := func() (tls.Certificate, error) {
+		return cert, nil
+	}
+
+	_, err := tlsconfig.NewServerConfig(tlsconfig.TLSCertWithExpiryCheck(provider, time.Minute))
+	assert.NoError(t, err)
+
+	_, err = tlsconfig.NewServerConfig(tlsconfig.TLSCertWithExpiryCheck(provider, 24*time.Hour))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to load TLS certificate: certificate "CN=expiring" expires at`)
+}
+
+func TestCertPoolOptionFromCAFilesWithExpiryCheck(t *testing.T) {
+	_, err := tlsconfig.CertPoolFromCertPoolOptions([]tlsconfig.CertPoolOption{
+		tlsconfig.CertPoolOptionFromCAFilesWithExpiryCheck(24*time.Hour, caCertFile),
+	})()
+	assert.NoError(t, err)
+
+	_, err = tlsconfig.CertPoolFromCertPoolOptions([]tlsconfig.CertPoolOption{
+		tlsconfig.CertPoolOptionFromCAFilesWithExpiryCheck(100*365*24*time.Hour, caCertFile),
+	})()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `certificate "O=Root CA" expires at`)
+}
+
+func TestExpiryMonitor(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "test"},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(48 * time.Hour),
+	}, false)
+
+	expiries := make(map[string]time.Time)
+	monitor := tlsconfig.NewExpiryMonitor(func(name string, notAfter time.Time) {
+		expiries[name] = notAfter
+	})
+	monitor.AddTLSCert("server", func() (tls.Certificate, error) {
+		return cert, nil
+	})
+	monitor.AddCAFiles("ca", caCertFile)
+	require.NoError(t, monitor.Update())
+
+	require.Len(t, expiries, 2)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), expiries["server"], time.Minute)
+	assert.True(t, expiries["ca"].After(expiries["server"]))
+
+	monitor.AddCAFiles("missing", "testdata/missing.pem")
+	err := monitor.Update()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load certificates for missing: failed to load certificates from file testdata/missing.pem")
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// PassphraseProvider returns the passphrase used to decrypt a private key or PKCS #12 file. It is called every time the
+// encrypted data is loaded, so passphrases do not need to be held in memory between loads.
+type PassphraseProvider func() ([]byte, error)
+
+// StaticPassphrase returns a PassphraseProvider that returns the provided passphrase.
+func StaticPassphrase(passphrase string) PassphraseProvider {
+	return func() ([]byte, error) {
+		return []byte(passphrase), nil
+	}
+}
+
+// PassphraseFromFile returns a PassphraseProvider that returns the contents of the file at the provided path with any
+// trailing newline removed.
+func PassphraseFromFile(path string) PassphraseProvider {
+	return func() ([]byte, error) {
+		passphrase, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file %s: %v", path, err)
+		}
+		return bytes.TrimRight(passphrase, "\r\n"), nil
+	}
+}
+
+// TLSCertFromEncryptedPEMFiles returns a provider that returns a tls.Certificate by loading the PEM-encoded certificate
+// chain and private key from the files in the specified locations. The private key may be an encrypted PKCS #8 key
+// ("ENCRYPTED PRIVATE KEY") or a legacy OpenSSL encrypted PEM key (with a "Proc-Type: 4,ENCRYPTED" header), which is
+// decrypted using the passphrase returned by the provided PassphraseProvider. Unencrypted keys are also supported, in
+// which case the passphrase is not used.
+func TLSCertFromEncryptedPEMFiles(certFile, keyFile string, passphrase PassphraseProvider) TLSCertProvider {
+	return func() (tls.Certificate, error) {
+		certPEM, err := os.ReadFile(certFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to read certificate file %s: %v", certFile, err)
+		}
+		keyPEM, err := os.ReadFile(keyFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to read key file %s: %v", keyFile, err)
+		}
+		return x509KeyPairWithPassphrase(certPEM, keyPEM, passphrase)
+	}
+}
+
+// TLSCertFromCombinedPEMFile returns a provider that returns a tls.Certificate by loading the PEM-encoded certificate
+// chain and private key from a single file that contains both. The private key may be encrypted in any of the formats
+// supported by TLSCertFromEncryptedPEMFiles. The passphrase may be nil if the private key is not encrypted.
+func TLSCertFromCombinedPEMFile(file string, passphrase PassphraseProvider) TLSCertProvider {
+	return func() (tls.Certificate, error) {
+		pemBytes, err := os.ReadFile(file)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to read file %s: %v", file, err)
+		}
+		return x509KeyPairWithPassphrase(pemBytes, pemBytes, passphrase)
+	}
+}
+
+// TLSCertFromPKCS12File returns a provider that returns a tls.Certificate by loading the private key, certificate and
+// CA certificates from a PKCS #12 (PFX) file, which is decrypted using the passphrase returned by the provided
+// PassphraseProvider. The CA certificates in the file are included in the certificate chain.
+func TLSCertFromPKCS12File(file string, passphrase PassphraseProvider) TLSCertProvider {
+	return func() (tls.Certificate, error) {
+		pfxData, err := os.ReadFile(file)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to read PKCS #12 file %s: %v", file, err)
+		}
+		password, err := loadPassphrase(passphrase)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		key, cert, caCerts, err := pkcs12.DecodeChain(pfxData, string(password))
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to decode PKCS #12 file %s: %v", file, err)
+		}
+		tlsCert := tls.Certificate{
+			Certificate: [][]byte{cert.Raw},
+			PrivateKey:  key,
+			Leaf:        cert,
+		}
+		for _, caCert := range caCerts {
+			tlsCert.Certificate = append(tlsCert.Certificate, caCert.Raw)
+		}
+		return tlsCert, nil
+	}
+}
+
+// CertPoolOptionFromPKCS12Truststore returns a CertPoolOption that adds the trusted certificates in a PKCS #12
+// truststore file (as created by Java's keytool) to the pool. The file is decrypted using the passphrase returned by
+// the provided PassphraseProvider.
+func CertPoolOptionFromPKCS12Truststore(file string, passphrase PassphraseProvider) CertPoolOption {
+	return func(certPool *x509.CertPool) error {
+		pfxData, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read PKCS #12 truststore %s: %v", file, err)
+		}
+		password, err := loadPassphrase(passphrase)
+		if err != nil {
+			return err
+		}
+		certs, err := pkcs12.DecodeTrustStore(pfxData, string(password))
+		if err != nil {
+			return fmt.Errorf("failed to decode PKCS #12 truststore %s: %v", file, err)
+		}
+		if len(certs) == 0 {
+			return fmt.Errorf("no certificates detected in PKCS #12 truststore %s", file)
+		}
+		for _, cert := range certs {
+			certPool.AddCert(cert)
+		}
+		return nil
+	}
+}
+
+// CertPoolOptionFromPEMDirectory returns a CertPoolOption that adds the certificates in every file in the provided
+// directory with a ".pem", ".crt" or ".cer" extension to the pool. Subdirectories are not read. Returns an error if a
+// file cannot be read or does not contain any certificates, or if the directory does not contain any such files.
+func CertPoolOptionFromPEMDirectory(dir string) CertPoolOption {
+	return func(certPool *x509.CertPool) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read certificate directory %s: %v", dir, err)
+		}
+		var caFiles []string
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".pem", ".crt", ".cer":
+				caFiles = append(caFiles, filepath.Join(dir, entry.Name()))
+			}
+		}
+		if len(caFiles) == 0 {
+			return fmt.Errorf("no certificate files detected in directory %s", dir)
+		}
+		return CertPoolOptionFromCAFiles(caFiles...)(certPool)
+	}
+}
+
+// x509KeyPairWithPassphrase is identical to tls.X509KeyPair, but decrypts the first private key in keyPEM if it is
+// encrypted.
+func x509KeyPairWithPassphrase(certPEM, keyPEM []byte, passphrase PassphraseProvider) (tls.Certificate, error) {
+	block := firstPrivateKeyBlock(keyPEM)
+	switch {
+	case block == nil:
+		return tls.X509KeyPair(certPEM, keyPEM)
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		password, err := loadPassphrase(passphrase)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, password)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to decrypt private key: %v", err)
+		}
+		keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to marshal private key: %v", err)
+		}
+		return tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	case x509.IsEncryptedPEMBlock(block):
+		// legacy encrypted PEM keys are insecure, but are still produced by common tooling
+		password, err := loadPassphrase(passphrase)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		keyDER, err := x509.DecryptPEMBlock(block, password)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to decrypt private key: %v", err)
+		}
+		return tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: keyDER}))
+	default:
+		return tls.X509KeyPair(certPEM, keyPEM)
+	}
+}
+
+func firstPrivateKeyBlock(pemBytes []byte) *pem.Block {
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil || strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			return block
+		}
+	}
+}
+
+func loadPassphrase(passphrase PassphraseProvider) ([]byte, error) {
+	if passphrase == nil {
+		return nil, fmt.Errorf("private key is encrypted but no passphrase was provided")
+	}
+	password, err := passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load passphrase: %v", err)
+	}
+	return password, nil
+}
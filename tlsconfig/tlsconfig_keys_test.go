@@ -0,0 +1,192 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig_test
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/palantir/pkg/tlsconfig/tlsconfigtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestTLSCertFromEncryptedPEMFiles(t *testing.T) {
+	cert := tlsconfigtest.NewCA(t).NewIntermediate(t).NewServerCert(t, tlsconfigtest.WithKeyType(tlsconfigtest.RSA2048))
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	writeTestFile(t, certFile, cert.CertPEM())
+
+	pkcs8DER, err := pkcs8.MarshalPrivateKey(cert.Key, []byte("secret"), nil)
+	require.NoError(t, err)
+	pkcs8KeyFile := filepath.Join(dir, "pkcs8.pem")
+	writeTestFile(t, pkcs8KeyFile, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: pkcs8DER}))
+
+	legacyBlock, err := x509.EncryptPEMBlock(rand.Reader, "PRIVATE KEY", pemBlockBytes(t, cert.KeyPEM()), []byte("secret"), x509.PEMCipherAES256)
+	require.NoError(t, err)
+	legacyKeyFile := filepath.Join(dir, "legacy.pem")
+	writeTestFile(t, legacyKeyFile, pem.EncodeToMemory(legacyBlock))
+
+	plainKeyFile := filepath.Join(dir, "key.pem")
+	writeTestFile(t, plainKeyFile, cert.KeyPEM())
+
+	passphraseFile := filepath.Join(dir, "passphrase")
+	writeTestFile(t, passphraseFile, []byte("secret\n"))
+
+	for _, tc := range []struct {
+		name       string
+		keyFile    string
+		passphrase tlsconfig.PassphraseProvider
+		wantErr    string
+	}{
+		{
+			name:       "encrypted PKCS #8 key",
+			keyFile:    pkcs8KeyFile,
+			passphrase: tlsconfig.StaticPassphrase("secret"),
+		},
+		{
+			name:       "legacy encrypted PEM key",
+			keyFile:    legacyKeyFile,
+			passphrase: tlsconfig.PassphraseFromFile(passphraseFile),
+		},
+		{
+			name:    "unencrypted key without passphrase",
+			keyFile: plainKeyFile,
+		},
+		{
+			name:       "wrong passphrase",
+			keyFile:    pkcs8KeyFile,
+			passphrase: tlsconfig.StaticPassphrase("wrong"),
+			wantErr:    "failed to decrypt private key",
+		},
+		{
+			name:       "wrong legacy passphrase",
+			keyFile:    legacyKeyFile,
+			passphrase: tlsconfig.StaticPassphrase("wrong"),
+			wantErr:    "failed to decrypt private key",
+		},
+		{
+			name:    "missing passphrase",
+			keyFile: pkcs8KeyFile,
+			wantErr: "private key is encrypted but no passphrase was provided",
+		},
+		{
+			name:       "missing passphrase file",
+			keyFile:    pkcs8KeyFile,
+			passphrase: tlsconfig.PassphraseFromFile(filepath.Join(dir, "missing")),
+			wantErr:    "failed to load passphrase: failed to read passphrase file",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tlsCert, err := tlsconfig.TLSCertFromEncryptedPEMFiles(certFile, tc.keyFile, tc.passphrase)()
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, cert.Chain, tlsCert.Certificate)
+			assert.Equal(t, cert.Leaf, tlsCert.Leaf)
+		})
+	}
+}
+
+func TestTLSCertFromCombinedPEMFile(t *testing.T) {
+	cert := tlsconfigtest.NewCA(t).NewServerCert(t)
+	dir := t.TempDir()
+
+	combinedFile := filepath.Join(dir, "combined.pem")
+	writeTestFile(t, combinedFile, append(cert.KeyPEM(), cert.CertPEM()...))
+	tlsCert, err := tlsconfig.TLSCertFromCombinedPEMFile(combinedFile, nil)()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Chain, tlsCert.Certificate)
+
+	keyDER, err := pkcs8.MarshalPrivateKey(cert.Key, []byte("secret"), nil)
+	require.NoError(t, err)
+	encryptedFile := filepath.Join(dir, "encrypted.pem")
+	writeTestFile(t, encryptedFile, append(cert.CertPEM(), pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: keyDER})...))
+	tlsCert, err = tlsconfig.TLSCertFromCombinedPEMFile(encryptedFile, tlsconfig.StaticPassphrase("secret"))()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Chain, tlsCert.Certificate)
+}
+
+func TestTLSCertFromPKCS12File(t *testing.T) {
+	intermediate := tlsconfigtest.NewCA(t).NewIntermediate(t)
+	cert := intermediate.NewServerCert(t)
+	pfxData, err := pkcs12.Modern.Encode(cert.Key, cert.Leaf, []*x509.Certificate{intermediate.Cert}, "secret")
+	require.NoError(t, err)
+	pfxFile := filepath.Join(t.TempDir(), "cert.p12")
+	writeTestFile(t, pfxFile, pfxData)
+
+	tlsCert, err := tlsconfig.TLSCertFromPKCS12File(pfxFile, tlsconfig.StaticPassphrase("secret"))()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Chain, tlsCert.Certificate)
+	assert.Equal(t, cert.Leaf, tlsCert.Leaf)
+
+	_, err = tlsconfig.TLSCertFromPKCS12File(pfxFile, tlsconfig.StaticPassphrase("wrong"))()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode PKCS #12 file")
+
+	_, err = tlsconfig.TLSCertFromPKCS12File(pfxFile, nil)()
+	assert.EqualError(t, err, "private key is encrypted but no passphrase was provided")
+}
+
+func TestCertPoolOptionFromPKCS12Truststore(t *testing.T) {
+	ca := tlsconfigtest.NewCA(t)
+	otherCA := tlsconfigtest.NewCA(t)
+	pfxData, err := pkcs12.Modern.EncodeTrustStore([]*x509.Certificate{ca.Cert, otherCA.Cert}, "changeit")
+	require.NoError(t, err)
+	pfxFile := filepath.Join(t.TempDir(), "truststore.p12")
+	writeTestFile(t, pfxFile, pfxData)
+
+	certPool := x509.NewCertPool()
+	require.NoError(t, tlsconfig.CertPoolOptionFromPKCS12Truststore(pfxFile, tlsconfig.StaticPassphrase("changeit"))(certPool))
+	for _, cert := range []*tlsconfigtest.Cert{ca.NewServerCert(t), otherCA.NewServerCert(t)} {
+		_, err := cert.Leaf.Verify(x509.VerifyOptions{Roots: certPool})
+		assert.NoError(t, err)
+	}
+
+	err = tlsconfig.CertPoolOptionFromPKCS12Truststore(pfxFile, tlsconfig.StaticPassphrase("wrong"))(x509.NewCertPool())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode PKCS #12 truststore")
+}
+
+func TestCertPoolOptionFromPEMDirectory(t *testing.T) {
+	ca := tlsconfigtest.NewCA(t)
+	otherCA := tlsconfigtest.NewCA(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "ca.pem"), ca.CertPEM())
+	writeTestFile(t, filepath.Join(dir, "other.CRT"), otherCA.CertPEM())
+	writeTestFile(t, filepath.Join(dir, "README"), []byte("not a certificate"))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "subdir.pem"), 0755))
+
+	certPool := x509.NewCertPool()
+	require.NoError(t, tlsconfig.CertPoolOptionFromPEMDirectory(dir)(certPool))
+	for _, cert := range []*tlsconfigtest.Cert{ca.NewServerCert(t), otherCA.NewServerCert(t)} {
+		_, err := cert.Leaf.Verify(x509.VerifyOptions{Roots: certPool})
+		assert.NoError(t, err)
+	}
+
+	emptyDir := t.TempDir()
+	err := tlsconfig.CertPoolOptionFromPEMDirectory(emptyDir)(x509.NewCertPool())
+	assert.EqualError(t, err, "no certificate files detected in directory "+emptyDir)
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
+
+func pemBlockBytes(t *testing.T, pemBytes []byte) []byte {
+	block, _ := pem.Decode(pemBytes)
+	require.NotNil(t, block)
+	return block.Bytes
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"slices"
+)
+
+// Profile is a named TLS policy that specifies the protocol versions, cipher suites, key exchange curves and session
+// ticket settings that a client or server may use. Profiles are applied using ClientProfile and ServerProfile, and a
+// tls.Config can be compared against a profile using AuditConfig.
+type Profile string
+
+const (
+	// Modern allows only TLS 1.3. Cipher suites cannot be configured for TLS 1.3, so the profile does not restrict
+	// them. It is suitable when all clients and servers are known to support TLS 1.3.
+	Modern Profile = "modern"
+	// Intermediate allows TLS 1.2 and TLS 1.3 with forward-secret AEAD cipher suites. It matches the defaults of
+	// NewClientConfig and NewServerConfig and is suitable for most services.
+	Intermediate Profile = "intermediate"
+	// FIPS allows TLS 1.2 and TLS 1.3 with only the cipher suites and curves that are approved by FIPS 140 (ECDHE key
+	// exchange over the NIST P-256 and P-384 curves with AES-GCM) and disables session tickets. Using this profile
+	// does not make a binary FIPS 140 compliant: the cryptographic module must also be validated, and the TLS 1.3
+	// cipher suites are selected by crypto/tls.
+	FIPS Profile = "fips"
+)
+
+// profileSettings are the tls.Config settings required by a Profile.
+type profileSettings struct {
+	minVersion uint16
+	// maxVersion is the maximum allowed version, or 0 if the maximum version supported by crypto/tls is allowed.
+	maxVersion uint16
+	// cipherSuites are the allowed TLS 1.2 cipher suites. Empty if the profile does not allow TLS 1.2.
+	cipherSuites           []uint16
+	curvePreferences       []tls.CurveID
+	sessionTicketsDisabled bool
+}
+
+var profiles = map[Profile]profileSettings{
+	Modern: {
+		minVersion:       tls.VersionTLS13,
+		curvePreferences: []tls.CurveID{tls.X25519MLKEM768, tls.X25519, tls.CurveP256, tls.CurveP384, tls.CurveP521},
+	},
+	Intermediate: {
+		minVersion: tls.VersionTLS12,
+		cipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+		curvePreferences: []tls.CurveID{tls.X25519MLKEM768, tls.X25519, tls.CurveP256, tls.CurveP384, tls.CurveP521},
+	},
+	FIPS: {
+		minVersion: tls.VersionTLS12,
+		cipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		},
+		curvePreferences:       []tls.CurveID{tls.CurveP256, tls.CurveP384},
+		sessionTicketsDisabled: true,
+	},
+}
+
+// defaultCurvePreferences are the curves used by crypto/tls when CurvePreferences is not set.
+var defaultCurvePreferences = []tls.CurveID{tls.X25519MLKEM768, tls.X25519, tls.CurveP256, tls.CurveP384, tls.CurveP521}
+
+// ClientProfile configures the client with the settings required by the provided profile. Parameters that are
+// provided after this parameter (such as ClientCipherSuites) override the profile's settings.
+func ClientProfile(profile Profile) ClientParam {
+	return clientParam(profileParam(profile))
+}
+
+// ServerProfile configures the server with the settings required by the provided profile. Parameters that are
+// provided after this parameter (such as ServerCipherSuites) override the profile's settings.
+func ServerProfile(profile Profile) ServerParam {
+	return serverParam(profileParam(profile))
+}
+
+// ClientMinVersion sets the minimum TLS version supported by the client. If this parameter is not provided, defaults
+// to TLS 1.2.
+func ClientMinVersion(version uint16) ClientParam {
+	return clientParam(minVersionParam(version))
+}
+
+// ClientMaxVersion sets the maximum TLS version supported by the client. If this parameter is not provided, the maximum
+// version supported by crypto/tls is used.
+func ClientMaxVersion(version uint16) ClientParam {
+	return clientParam(maxVersionParam(version))
+}
+
+// ServerMinVersion sets the minimum TLS version supported by the server. If this parameter is not provided, defaults
+// to TLS 1.2.
+func ServerMinVersion(version uint16) ServerParam {
+	return serverParam(minVersionParam(version))
+}
+
+// ServerMaxVersion sets the maximum TLS version supported by the server. If this parameter is not provided, the maximum
+// version supported by crypto/tls is used.
+func ServerMaxVersion(version uint16) ServerParam {
+	return serverParam(maxVersionParam(version))
+}
+
+// AuditConfig compares the provided tls.Config against the provided profile and returns a description of each setting
+// that the profile does not allow. Settings that are not set in the tls.Config are compared using the defaults of
+// crypto/tls. Returns no deviations if the tls.Config conforms to the profile.
+func AuditConfig(cfg *tls.Config, profile Profile) ([]string, error) {
+	settings, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown TLS profile %q", profile)
+	}
+	var deviations []string
+
+	minVersion := cfg.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+	if minVersion < settings.minVersion {
+		deviations = append(deviations, fmt.Sprintf("MinVersion %s is lower than %s", tls.VersionName(minVersion), tls.VersionName(settings.minVersion)))
+	}
+	if settings.maxVersion != 0 && (cfg.MaxVersion == 0 || cfg.MaxVersion > settings.maxVersion) {
+		maxVersion := "the maximum version supported by crypto/tls"
+		if cfg.MaxVersion != 0 {
+			maxVersion = tls.VersionName(cfg.MaxVersion)
+		}
+		deviations = append(deviations, fmt.Sprintf("MaxVersion %s is higher than %s", maxVersion, tls.VersionName(settings.maxVersion)))
+	}
+
+	// cipher suites are only configurable for TLS 1.2 and lower, so they are only audited if both the config and the
+	// profile allow TLS 1.2
+	if minVersion < tls.VersionTLS13 && settings.minVersion < tls.VersionTLS13 {
+		if len(cfg.CipherSuites) == 0 {
+			deviations = append(deviations, "CipherSuites is not set, so the default cipher suites of crypto/tls are used")
+		}
+		for _, suite := range cfg.CipherSuites {
+			if isTLS13CipherSuite(suite) {
+				continue
+			}
+			if !slices.Contains(settings.cipherSuites, suite) {
+				deviations = append(deviations, fmt.Sprintf("cipher suite %s is not allowed", tls.CipherSuiteName(suite)))
+			}
+		}
+	}
+
+	curves := cfg.CurvePreferences
+	if len(curves) == 0 {
+		curves = defaultCurvePreferences
+	}
+	for _, curve := range curves {
+		if !slices.Contains(settings.curvePreferences, curve) {
+			deviations = append(deviations, fmt.Sprintf("curve %s is not allowed", curve))
+		}
+	}
+
+	if settings.sessionTicketsDisabled && !cfg.SessionTicketsDisabled {
+		deviations = append(deviations, "session tickets are not disabled")
+	}
+	if cfg.InsecureSkipVerify && cfg.VerifyConnection == nil && cfg.VerifyPeerCertificate == nil {
+		deviations = append(deviations, "InsecureSkipVerify is set and no custom verification is configured")
+	}
+	return deviations, nil
+}
+
+func profileParam(profile Profile) configurer {
+	return func(cfg *tls.Config) error {
+		settings, ok := profiles[profile]
+		if !ok {
+			return fmt.Errorf("unknown TLS profile %q", profile)
+		}
+		cfg.MinVersion = settings.minVersion
+		cfg.MaxVersion = settings.maxVersion
+		cfg.CipherSuites = slices.Clone(settings.cipherSuites)
+		cfg.CurvePreferences = slices.Clone(settings.curvePreferences)
+		cfg.SessionTicketsDisabled = settings.sessionTicketsDisabled
+		return nil
+	}
+}
+
+func minVersionParam(version uint16) configurer {
+	return func(cfg *tls.Config) error {
+		cfg.MinVersion = version
+		return nil
+	}
+}
+
+func maxVersionParam(version uint16) configurer {
+	return func(cfg *tls.Config) error {
+		cfg.MaxVersion = version
+		return nil
+	}
+}
+
+func isTLS13CipherSuite(suite uint16) bool {
+	switch suite {
+	case tls.TLS_AES_128_GCM_SHA256, tls.TLS_AES_256_GCM_SHA384, tls.TLS_CHACHA20_POLY1305_SHA256:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig_test
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	for _, profile := range []tlsconfig.Profile{tlsconfig.Modern, tlsconfig.Intermediate, tlsconfig.FIPS} {
+		t.Run(string(profile), func(t *testing.T) {
+			serverCfg, err := tlsconfig.NewServerConfig(
+				tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+				tlsconfig.ServerProfile(profile),
+			)
+			require.NoError(t, err)
+			deviations, err := tlsconfig.AuditConfig(serverCfg, profile)
+			require.NoError(t, err)
+			assert.Empty(t, deviations)
+
+			clientCfg, err := tlsconfig.NewClientConfig(
+				tlsconfig.ClientRootCAFiles(caCertFile),
+				tlsconfig.ClientProfile(profile),
+			)
+			require.NoError(t, err)
+			deviations, err = tlsconfig.AuditConfig(clientCfg, profile)
+			require.NoError(t, err)
+			assert.Empty(t, deviations)
+
+			addr, _ := startVerifyTestServer(t, serverCfg)
+			clientCfg.ServerName = "localhost"
+			require.NoError(t, dialVerifyTestServer(addr, clientCfg))
+		})
+	}
+}
+
+func TestProfiles_Modern(t *testing.T) {
+	serverCfg, err := tlsconfig.NewServerConfig(
+		tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+		tlsconfig.ServerProfile(tlsconfig.Modern),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), serverCfg.MinVersion)
+	addr, _ := startVerifyTestServer(t, serverCfg)
+
+	clientCfg, err := tlsconfig.NewClientConfig(
+		tlsconfig.ClientRootCAFiles(caCertFile),
+		tlsconfig.ClientMaxVersion(tls.VersionTLS12),
+	)
+	require.NoError(t, err)
+	clientCfg.ServerName = "localhost"
+	err = dialVerifyTestServer(addr, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "protocol version not supported")
+}
+
+func TestAuditConfig(t *testing.T) {
+	defaultClientCfg, err := tlsconfig.NewClientConfig()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		cfg     *tls.Config
+		profile tlsconfig.Profile
+		want    []string
+	}{
+		{
+			name:    "default client config conforms to intermediate",
+			cfg:     defaultClientCfg,
+			profile: tlsconfig.Intermediate,
+		},
+		{
+			name:    "default client config does not conform to modern",
+			cfg:     defaultClientCfg,
+			profile: tlsconfig.Modern,
+			want:    []string{"MinVersion TLS 1.2 is lower than TLS 1.3"},
+		},
+		{
+			name:    "default client config does not conform to FIPS",
+			cfg:     defaultClientCfg,
+			profile: tlsconfig.FIPS,
+			want: []string{
+				"cipher suite TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256 is not allowed",
+				"cipher suite TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256 is not allowed",
+				"curve X25519MLKEM768 is not allowed",
+				"curve X25519 is not allowed",
+				"curve CurveP521 is not allowed",
+				"session tickets are not disabled",
+			},
+		},
+		{
+			name:    "empty config",
+			cfg:     &tls.Config{MinVersion: tls.VersionTLS10, InsecureSkipVerify: true},
+			profile: tlsconfig.Intermediate,
+			want: []string{
+				"MinVersion TLS 1.0 is lower than TLS 1.2",
+				"CipherSuites is not set, so the default cipher suites of crypto/tls are used",
+				"InsecureSkipVerify is set and no custom verification is configured",
+			},
+		},
+		{
+			name: "weak cipher suite",
+			cfg: &tls.Config{
+				CipherSuites:     []uint16{tls.TLS_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA},
+				CurvePreferences: []tls.CurveID{tls.CurveP256},
+			},
+			profile: tlsconfig.Intermediate,
+			want:    []string{"cipher suite TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA is not allowed"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			deviations, err := tlsconfig.AuditConfig(tc.cfg, tc.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, deviations)
+		})
+	}
+
+	_, err = tlsconfig.AuditConfig(defaultClientCfg, "unknown")
+	assert.EqualError(t, err, `unknown TLS profile "unknown"`)
+}
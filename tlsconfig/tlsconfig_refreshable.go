@@ -0,0 +1,247 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+
+	"github.com/palantir/pkg/refreshable/v2"
+)
+
+// PEMKeyPair is a PEM-encoded certificate chain and the PEM-encoded private key for the leaf certificate.
+type PEMKeyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// RefreshableKeyPairFiles returns a Refreshable whose value is the contents of the provided certificate and key files.
+// The files are re-read using refreshable.NewFileRefreshable until the provided context is done. Returns an error if
+// either file cannot be read initially.
+func RefreshableKeyPairFiles(ctx context.Context, certFile, keyFile string) (refreshable.Refreshable[PEMKeyPair], error) {
+	merged, _ := refreshable.MergeValidated(
+		refreshable.NewFileRefreshable(ctx, certFile),
+		refreshable.NewFileRefreshable(ctx, keyFile),
+		func(cert, key []byte) PEMKeyPair {
+			return PEMKeyPair{Cert: cert, Key: key}
+		},
+	)
+	r, _, err := refreshable.MapFromValidatedChecked(merged, func(keyPair PEMKeyPair) PEMKeyPair {
+		return keyPair
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key pair files %s and %s: %v", certFile, keyFile, err)
+	}
+	return r, nil
+}
+
+// RefreshableCAFiles returns a Refreshable whose value is the concatenated contents of the provided CA files. The files
+// are re-read using refreshable.NewFileRefreshable until the provided context is done. Returns an error if any of the
+// files cannot be read initially.
+func RefreshableCAFiles(ctx context.Context, caFiles ...string) (refreshable.Refreshable[[]byte], error) {
+	files := make([]refreshable.Validated[[]byte], len(caFiles))
+	for i, caFile := range caFiles {
+		files[i] = refreshable.NewFileRefreshable(ctx, caFile)
+	}
+	collected, _ := refreshable.CollectValidated(files...)
+	r, _, err := refreshable.MapFromValidatedChecked(collected, func(contents [][]byte) []byte {
+		return bytes.Join(contents, []byte("\n"))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA files: %v", err)
+	}
+	return r, nil
+}
+
+// TLSCertFromRefreshable returns a provider that returns the tls.Certificate parsed from the current value of the
+// provided Refreshable. The key pair is parsed when the Refreshable is updated rather than every time the provider is
+// called. If an updated value cannot be parsed (for example, because the certificate has been rotated but the key has
+// not yet been), the provider continues to return the most recent valid certificate.
+func TLSCertFromRefreshable(keyPair refreshable.Refreshable[PEMKeyPair]) TLSCertProvider {
+	parsed := newParsedRefreshable(keyPair, func(keyPair PEMKeyPair) (tls.Certificate, error) {
+		return tls.X509KeyPair(keyPair.Cert, keyPair.Key)
+	})
+	return parsed.current
+}
+
+// CertPoolFromRefreshable returns a provider that returns a *x509.CertPool containing the PEM-encoded certificates in
+// the current value of the provided Refreshable. The certificates are parsed when the Refreshable is updated rather than
+// every time the provider is called. If an updated value does not contain any certificates, the provider continues to
+// return the most recent valid pool.
+func CertPoolFromRefreshable(caPEM refreshable.Refreshable[[]byte]) CertPoolProvider {
+	parsed := newParsedRefreshable(caPEM, func(caPEM []byte) (*x509.CertPool, error) {
+		certPool := x509.NewCertPool()
+		if err := CertPoolOptionCABytes(caPEM)(certPool); err != nil {
+			return nil, err
+		}
+		return certPool, nil
+	})
+	return parsed.current
+}
+
+// NewRefreshableServerConfig returns a tls.Config that is suitable to use by a server in 2-way TLS connections
+// configured with the provided parameters. Unlike NewServerConfig, the certificate presented to clients is the
+// certificate parsed from the current value of the provided Refreshable at the time of each handshake, so rotated
+// certificates take effect for new connections without rebuilding the returned tls.Config. Returns an error if the
+// initial value of the Refreshable is not a valid key pair.
+func NewRefreshableServerConfig(keyPair refreshable.Refreshable[PEMKeyPair], params ...ServerParam) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:               tls.VersionTLS12,
+		PreferServerCipherSuites: true,
+		CipherSuites:             defaultCipherSuites,
+		Renegotiation:            tls.RenegotiateNever,
+	}
+	if keyPair == nil {
+		return nil, fmt.Errorf("keyPair provided to NewRefreshableServerConfig was nil")
+	}
+	configurers := []configurer{getCertificateParam(TLSCertFromRefreshable(keyPair))}
+	for _, p := range params {
+		configurers = append(configurers, configurer(p.configureServer))
+	}
+	return configureTLSConfig(tlsCfg, configurers...)
+}
+
+// ServerClientCAsRefreshable configures the server to verify the certificates provided by clients using the
+// PEM-encoded CA certificates in the current value of the provided Refreshable at the time of each handshake, so
+// updated CA bundles take effect for new connections without rebuilding the tls.Config. The server's
+// GetConfigForClient function is used to provide the CAs: if the base configuration already sets GetConfigForClient,
+// the CAs are set on the configuration that it returns.
+func ServerClientCAsRefreshable(caPEM refreshable.Refreshable[[]byte]) ServerParam {
+	return serverParam(func(cfg *tls.Config) error {
+		if caPEM == nil {
+			return fmt.Errorf("caPEM provided to ServerClientCAsRefreshable was nil")
+		}
+		certPoolProvider := CertPoolFromRefreshable(caPEM)
+		if _, err := certPoolProvider(); err != nil {
+			return fmt.Errorf("failed to create certificate pool: %v", err)
+		}
+		prevGetConfigForClient := cfg.GetConfigForClient
+		cfg.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			baseCfg := cfg
+			if prevGetConfigForClient != nil {
+				prevCfg, err := prevGetConfigForClient(hello)
+				if err != nil {
+					return nil, err
+				}
+				if prevCfg != nil {
+					baseCfg = prevCfg
+				}
+			}
+			certPool, err := certPoolProvider()
+			if err != nil {
+				return nil, err
+			}
+			clientCfg := baseCfg.Clone()
+			clientCfg.ClientCAs = certPool
+			return clientCfg, nil
+		}
+		return nil
+	})
+}
+
+// NewRefreshableClientConfig returns a tls.Config that is suitable to use by a client in 2-way TLS connections
+// configured with the provided parameters and that verifies the certificates provided by servers using the PEM-encoded
+// CA certificates in the current value of the provided Refreshable at the time of each handshake, so updated CA bundles
+// take effect for new connections without rebuilding the returned tls.Config. Returns an error if the parameters are
+// invalid or if the initial value of the Refreshable does not contain any certificates.
+//
+// The root CAs of a tls.Config cannot be changed once it is in use, so the default verification performed by
+// crypto/tls is disabled and the server's certificate chain is instead verified in VerifyConnection against the current
+// CAs and the server name sent using SNI. IP addresses are not sent using SNI, so connections to servers addressed by
+// IP address fail unless ClientVerifyHostname is provided, in which case the certificate is verified against its
+// hostname instead. The parameters must not set the root CAs or disable certificate verification.
+func NewRefreshableClientConfig(caPEM refreshable.Refreshable[[]byte], params ...ClientParam) (*tls.Config, error) {
+	if caPEM == nil {
+		return nil, fmt.Errorf("caPEM provided to NewRefreshableClientConfig was nil")
+	}
+	certPoolProvider := CertPoolFromRefreshable(caPEM)
+	if _, err := certPoolProvider(); err != nil {
+		return nil, fmt.Errorf("failed to create certificate pool: %v", err)
+	}
+	var hostname verifyHostnameParam
+	var cfgParams []ClientParam
+	for _, p := range params {
+		if h, ok := p.(verifyHostnameParam); ok {
+			if err := h.validate(); err != nil {
+				return nil, err
+			}
+			hostname = h
+			continue
+		}
+		cfgParams = append(cfgParams, p)
+	}
+	cfg, err := NewClientConfig(cfgParams...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.InsecureSkipVerify {
+		return nil, fmt.Errorf("NewRefreshableClientConfig cannot be used when InsecureSkipVerify is set")
+	}
+	if cfg.RootCAs != nil {
+		return nil, fmt.Errorf("root CAs provided to NewRefreshableClientConfig using ClientRootCAs would be ignored")
+	}
+	cfg.InsecureSkipVerify = true
+	cfg.VerifyConnection = verifyServerChain(certPoolProvider, func(cs tls.ConnectionState) (string, error) {
+		if hostname != "" {
+			return string(hostname), nil
+		}
+		if cs.ServerName == "" {
+			return "", fmt.Errorf("tls: cannot verify the server's certificate without a server name; use ClientVerifyHostname to connect to a server by IP address")
+		}
+		return cs.ServerName, nil
+	}, cfg.VerifyConnection)
+	return cfg, nil
+}
+
+func getCertificateParam(provider TLSCertProvider) configurer {
+	return func(cfg *tls.Config) error {
+		if _, err := provider(); err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %v", err)
+		}
+		cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, err := provider()
+			return &cert, err
+		}
+		return nil
+	}
+}
+
+// parsedRefreshable stores the result of parsing the values of a Refreshable. Values are parsed when the Refreshable
+// is updated. If a value fails to parse, the most recent successfully parsed value is retained.
+type parsedRefreshable[T, P any] struct {
+	mutex  sync.RWMutex
+	parsed P
+	valid  bool
+	err    error
+}
+
+func newParsedRefreshable[T, P any](r refreshable.Refreshable[T], parse func(T) (P, error)) *parsedRefreshable[T, P] {
+	p := &parsedRefreshable[T, P]{}
+	r.Subscribe(func(val T) {
+		parsed, err := parse(val)
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		if err != nil {
+			p.err = err
+			return
+		}
+		p.parsed, p.valid, p.err = parsed, true, nil
+	})
+	return p
+}
+
+func (p *parsedRefreshable[T, P]) current() (P, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if !p.valid {
+		var zero P
+		return zero, p.err
+	}
+	return p.parsed, nil
+}
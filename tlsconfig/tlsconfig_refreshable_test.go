@@ -0,0 +1,244 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/refreshable/v2"
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSCertFromRefreshable(t *testing.T) {
+	keyPair := refreshable.New(readKeyPair(t, serverCertFile, serverKeyFile))
+	provider := tlsconfig.TLSCertFromRefreshable(keyPair)
+
+	cert, err := provider()
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cert.Leaf.Subject.CommonName)
+
+	keyPair.Update(readKeyPair(t, clientCertFile, clientKeyFile))
+	cert, err = provider()
+	require.NoError(t, err)
+	assert.Equal(t, "client", cert.Leaf.Subject.CommonName)
+
+	// mismatched certificate and key is ignored
+	keyPair.Update(readKeyPair(t, serverCertFile, clientKeyFile))
+	cert, err = provider()
+	require.NoError(t, err)
+	assert.Equal(t, "client", cert.Leaf.Subject.CommonName)
+}
+
+func TestTLSCertFromRefreshable_InvalidInitialValue(t *testing.T) {
+	provider := tlsconfig.TLSCertFromRefreshable(refreshable.New(readKeyPair(t, serverCertFile, clientKeyFile)))
+	_, err := provider()
+	assert.EqualError(t, err, "tls: private key does not match public key")
+
+	_, err = tlsconfig.NewRefreshableServerConfig(refreshable.New(tlsconfig.PEMKeyPair{}))
+	assert.EqualError(t, err, "failed to load TLS certificate: tls: failed to find any PEM data in certificate input")
+}
+
+func TestCertPoolFromRefreshable(t *testing.T) {
+	caPEM := refreshable.New(readFile(t, caCertFile))
+	provider := tlsconfig.CertPoolFromRefreshable(caPEM)
+
+	initialPool, err := provider()
+	require.NoError(t, err)
+
+	caPEM.Update([]byte("not a certificate"))
+	pool, err := provider()
+	require.NoError(t, err)
+	assert.True(t, initialPool == pool)
+
+	caPEM.Update(readFile(t, clientCertFile))
+	pool, err = provider()
+	require.NoError(t, err)
+	assert.False(t, initialPool == pool)
+}
+
+func TestNewRefreshableServerConfig_RotatesCertificate(t *testing.T) {
+	keyPair := refreshable.New(readKeyPair(t, serverCertFile, serverKeyFile))
+	serverCfg, err := tlsconfig.NewRefreshableServerConfig(keyPair)
+	require.NoError(t, err)
+	server := startRefreshableTestServer(t, serverCfg)
+
+	clientCfg, err := tlsconfig.NewClientConfig(tlsconfig.ClientRootCAFiles(caCertFile))
+	require.NoError(t, err)
+	require.NoError(t, doRefreshableTestRequest(server, clientCfg))
+
+	// the client certificate is signed by the same CA but is not valid for localhost
+	keyPair.Update(readKeyPair(t, clientCertFile, clientKeyFile))
+	err = doRefreshableTestRequest(server, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate is not valid for any names")
+
+	keyPair.Update(readKeyPair(t, serverCertFile, serverKeyFile))
+	require.NoError(t, doRefreshableTestRequest(server, clientCfg))
+}
+
+func TestServerClientCAsRefreshable(t *testing.T) {
+	caPEM := refreshable.New(readFile(t, caCertFile))
+	serverCfg, err := tlsconfig.NewRefreshableServerConfig(
+		refreshable.New(readKeyPair(t, serverCertFile, serverKeyFile)),
+		tlsconfig.ServerClientAuthType(tls.RequireAndVerifyClientCert),
+		tlsconfig.ServerClientCAsRefreshable(caPEM),
+	)
+	require.NoError(t, err)
+	server := startRefreshableTestServer(t, serverCfg)
+
+	clientCfg, err := tlsconfig.NewClientConfig(
+		tlsconfig.ClientKeyPairFiles(clientCertFile, clientKeyFile),
+		tlsconfig.ClientRootCAFiles(caCertFile),
+	)
+	require.NoError(t, err)
+	require.NoError(t, doRefreshableTestRequest(server, clientCfg))
+
+	// trust only the server certificate, which did not sign the client certificate
+	caPEM.Update(readFile(t, serverCertFile))
+	require.Error(t, doRefreshableTestRequest(server, clientCfg))
+
+	caPEM.Update(readFile(t, caCertFile))
+	require.NoError(t, doRefreshableTestRequest(server, clientCfg))
+}
+
+func TestNewRefreshableClientConfig(t *testing.T) {
+	serverCfg, err := tlsconfig.NewServerConfig(tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile))
+	require.NoError(t, err)
+	server := startRefreshableTestServer(t, serverCfg)
+
+	caPEM := refreshable.New(readFile(t, caCertFile))
+	cfg, err := tlsconfig.NewRefreshableClientConfig(caPEM, tlsconfig.ClientKeyPairFiles(clientCertFile, clientKeyFile))
+	require.NoError(t, err)
+	assert.Len(t, cfg.Certificates, 1, "parameters are applied")
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, DisableKeepAlives: true}}
+	get := func(client *http.Client, url string) error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	localhostURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	require.NoError(t, get(client, localhostURL))
+
+	// trust only the client certificate, which did not sign the server certificate
+	caPEM.Update(readFile(t, clientCertFile))
+	err = get(client, localhostURL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+
+	caPEM.Update(readFile(t, caCertFile))
+	require.NoError(t, get(client, localhostURL))
+
+	// the server name must match the certificate
+	exampleCfg := cfg.Clone()
+	exampleCfg.ServerName = "example.com"
+	_, err = tls.Dial("tcp", server.Listener.Addr().String(), exampleCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate is valid for localhost, not example.com")
+
+	// IP addresses are not sent using SNI, so servers addressed by IP address require ClientVerifyHostname
+	err = get(client, server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use ClientVerifyHostname to connect to a server by IP address")
+	ipCfg, err := tlsconfig.NewRefreshableClientConfig(caPEM, tlsconfig.ClientVerifyHostname("127.0.0.1"))
+	require.NoError(t, err)
+	require.NoError(t, get(&http.Client{Transport: &http.Transport{TLSClientConfig: ipCfg, DisableKeepAlives: true}}, server.URL))
+
+	_, err = tlsconfig.NewRefreshableClientConfig(caPEM, tlsconfig.ClientInsecureSkipVerify())
+	assert.EqualError(t, err, "NewRefreshableClientConfig cannot be used when InsecureSkipVerify is set")
+	_, err = tlsconfig.NewRefreshableClientConfig(caPEM, tlsconfig.ClientRootCAFiles(caCertFile))
+	assert.EqualError(t, err, "root CAs provided to NewRefreshableClientConfig using ClientRootCAs would be ignored")
+}
+
+func TestRefreshableKeyPairFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, readFile(t, serverCertFile), 0644))
+	require.NoError(t, os.WriteFile(keyFile, readFile(t, serverKeyFile), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	keyPair, err := tlsconfig.RefreshableKeyPairFiles(ctx, certFile, keyFile)
+	require.NoError(t, err)
+	provider := tlsconfig.TLSCertFromRefreshable(keyPair)
+	cert, err := provider()
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cert.Leaf.Subject.CommonName)
+
+	require.NoError(t, os.WriteFile(certFile, readFile(t, clientCertFile), 0644))
+	require.NoError(t, os.WriteFile(keyFile, readFile(t, clientKeyFile), 0600))
+	assert.Eventually(t, func() bool {
+		cert, err := provider()
+		return err == nil && cert.Leaf.Subject.CommonName == "client"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	_, err = tlsconfig.RefreshableKeyPairFiles(ctx, filepath.Join(dir, "missing.pem"), keyFile)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to read key pair files"), err.Error())
+}
+
+func TestRefreshableCAFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	caPEM, err := tlsconfig.RefreshableCAFiles(ctx, caCertFile, clientCertFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(readFile(t, caCertFile))+"\n"+string(readFile(t, clientCertFile)), string(caPEM.Current()))
+
+	_, err = tlsconfig.RefreshableCAFiles(ctx, caCertFile, "testdata/missing.pem")
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to read CA files"), err.Error())
+}
+
+func startRefreshableTestServer(t *testing.T, cfg *tls.Config) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = cfg
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// doRefreshableTestRequest makes a request to the provided server using a new connection. The server is addressed as
+// localhost so that the client sends a server name.
+func doRefreshableTestRequest(server *httptest.Server, clientCfg *tls.Config) error {
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   clientCfg,
+			DisableKeepAlives: true,
+		},
+	}
+	resp, err := client.Get(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func readKeyPair(t *testing.T, certFile, keyFile string) tlsconfig.PEMKeyPair {
+	return tlsconfig.PEMKeyPair{
+		Cert: readFile(t, certFile),
+		Key:  readFile(t, keyFile),
+	}
+}
+
+func readFile(t *testing.T, path string) []byte {
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return b
+}
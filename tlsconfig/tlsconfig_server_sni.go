@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// SNICertificate is a certificate that a server presents to clients that request one of its hostnames using Server
+// Name Indication (SNI). A hostname may be a wildcard of the form "*.example.com", which matches server names with
+// exactly one label in place of the "*". A SNICertificate without any hostnames is a default certificate.
+type SNICertificate struct {
+	Hostnames []string
+	Provider  TLSCertProvider
+}
+
+// ServerSNICertificates configures the server to select the certificate it presents to each client based on the
+// server name requested by the client. Certificates are loaded from their providers once, when the tls.Config is
+// created, and an error is returned if the subject alternative names of a certificate do not cover all of its
+// hostnames.
+//
+// A certificate whose hostnames contain the requested server name is preferred, followed by a certificate with a
+// wildcard hostname that matches the server name. If no hostname matches or the client does not use SNI, the default
+// certificates are used: the certificate provided to NewServerConfig followed by the SNICertificates that do not have
+// any hostnames. Multiple certificates (for example, an RSA certificate and an ECDSA certificate) may be provided for
+// the same hostnames, in which case the first certificate that is supported by the client is presented.
+func ServerSNICertificates(certs ...SNICertificate) ServerParam {
+	return serverParam(func(cfg *tls.Config) error {
+		byHostname := make(map[string][]*tls.Certificate)
+		for _, sniCert := range certs {
+			if sniCert.Provider == nil {
+				return fmt.Errorf("provider for SNI certificate with hostnames %v was nil", sniCert.Hostnames)
+			}
+			cert, err := sniCert.Provider()
+			if err != nil {
+				return fmt.Errorf("failed to load TLS certificate for hostnames %v: %v", sniCert.Hostnames, err)
+			}
+			if len(sniCert.Hostnames) == 0 {
+				cfg.Certificates = append(cfg.Certificates, cert)
+				continue
+			}
+			leaf, err := leafCertificate(cert)
+			if err != nil {
+				return fmt.Errorf("failed to parse TLS certificate for hostnames %v: %v", sniCert.Hostnames, err)
+			}
+			for _, hostname := range sniCert.Hostnames {
+				hostname = normalizeServerName(hostname)
+				if err := verifyCertificateHostname(leaf, hostname); err != nil {
+					return err
+				}
+				byHostname[hostname] = append(byHostname[hostname], &cert)
+			}
+		}
+		prevGetCertificate := cfg.GetCertificate
+		cfg.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			serverName := normalizeServerName(hello.ServerName)
+			if candidates := byHostname[serverName]; len(candidates) > 0 {
+				return selectCertificate(hello, candidates), nil
+			}
+			if i := strings.IndexByte(serverName, '.'); i > 0 {
+				if candidates := byHostname["*"+serverName[i:]]; len(candidates) > 0 {
+					return selectCertificate(hello, candidates), nil
+				}
+			}
+			if prevGetCertificate != nil {
+				return prevGetCertificate(hello)
+			}
+			// returning a nil certificate causes crypto/tls to select from the default certificates in cfg.Certificates
+			return nil, nil
+		}
+		return nil
+	})
+}
+
+// selectCertificate returns the first of the provided certificates that is supported by the client, or the first
+// certificate if the client does not support any of them.
+func selectCertificate(hello *tls.ClientHelloInfo, candidates []*tls.Certificate) *tls.Certificate {
+	for _, cert := range candidates {
+		if err := hello.SupportsCertificate(cert); err == nil {
+			return cert
+		}
+	}
+	return candidates[0]
+}
+
+// verifyCertificateHostname returns an error if the subject alternative names of the certificate do not cover the
+// provided hostname. A wildcard hostname is covered only by the same wildcard DNS name.
+func verifyCertificateHostname(leaf *x509.Certificate, hostname string) error {
+	if strings.HasPrefix(hostname, "*.") {
+		for _, dnsName := range leaf.DNSNames {
+			if normalizeServerName(dnsName) == hostname {
+				return nil
+			}
+		}
+		return fmt.Errorf("certificate with DNS names %v is not valid for wildcard hostname %s", leaf.DNSNames, hostname)
+	}
+	if err := leaf.VerifyHostname(hostname); err != nil {
+		return fmt.Errorf("certificate is not valid for hostname %s: %v", hostname, err)
+	}
+	return nil
+}
+
+func leafCertificate(cert tls.Certificate) (*x509.Certificate, error) {
+	if cert.Leaf != nil {
+		return cert.Leaf, nil
+	}
+	if len(cert.Certificate) == 0 {
+		return nil, fmt.Errorf("no certificates found in TLS certificate")
+	}
+	return x509.ParseCertificate(cert.Certificate[0])
+}
+
+func normalizeServerName(serverName string) string {
+	return strings.TrimSuffix(strings.ToLower(serverName), ".")
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerSNICertificates(t *testing.T) {
+	serverCfg, err := tlsconfig.NewServerConfig(
+		tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+		tlsconfig.ServerSNICertificates(
+			tlsconfig.SNICertificate{
+				Hostnames: []string{"foo.example.com"},
+				Provider:  newSNITestCertProvider(t, "foo", false, "foo.example.com"),
+			},
+			tlsconfig.SNICertificate{
+				Hostnames: []string{"*.example.com"},
+				Provider:  newSNITestCertProvider(t, "wildcard", false, "*.example.com"),
+			},
+		),
+	)
+	require.NoError(t, err)
+	addr := startSNITestServer(t, serverCfg)
+
+	for _, tc := range []struct {
+		name       string
+		serverName string
+		wantCN     string
+	}{
+		{name: "exact match", serverName: "foo.example.com", wantCN: "foo"},
+		{name: "exact match is case insensitive", serverName: "FOO.example.com", wantCN: "foo"},
+		{name: "wildcard match", serverName: "bar.example.com", wantCN: "wildcard"},
+		{name: "wildcard matches a single label", serverName: "a.bar.example.com", wantCN: "localhost"},
+		{name: "no match uses default", serverName: "localhost", wantCN: "localhost"},
+		{name: "no SNI uses default", serverName: "", wantCN: "localhost"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cert := dialSNITestServer(t, addr, &tls.Config{ServerName: tc.serverName, InsecureSkipVerify: true})
+			assert.Equal(t, tc.wantCN, cert.Subject.CommonName)
+		})
+	}
+}
+
+func TestServerSNICertificates_SelectsByKeyType(t *testing.T) {
+	serverCfg, err := tlsconfig.NewServerConfig(
+		tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+		tlsconfig.ServerSNICertificates(
+			tlsconfig.SNICertificate{
+				Hostnames: []string{"foo.example.com"},
+				Provider:  newSNITestCertProvider(t, "ecdsa", true, "foo.example.com"),
+			},
+			tlsconfig.SNICertificate{
+				Hostnames: []string{"foo.example.com"},
+				Provider:  newSNITestCertProvider(t, "rsa", false, "foo.example.com"),
+			},
+		),
+	)
+	require.NoError(t, err)
+	addr := startSNITestServer(t, serverCfg)
+
+	caPool, err := tlsconfig.CertPoolFromCAFiles(caCertFile)()
+	require.NoError(t, err)
+
+	cert := dialSNITestServer(t, addr, &tls.Config{ServerName: "foo.example.com", RootCAs: caPool})
+	assert.Equal(t, "ecdsa", cert.Subject.CommonName)
+
+	cert = dialSNITestServer(t, addr, &tls.Config{
+		ServerName:   "foo.example.com",
+		RootCAs:      caPool,
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	})
+	assert.Equal(t, "rsa", cert.Subject.CommonName)
+}
+
+func TestServerSNICertificates_DefaultCertificates(t *testing.T) {
+	serverCfg, err := tlsconfig.NewServerConfig(
+		tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+		tlsconfig.ServerSNICertificates(tlsconfig.SNICertificate{
+			Provider: newSNITestCertProvider(t, "ecdsa-default", true, "localhost"),
+		}),
+	)
+	require.NoError(t, err)
+	require.Len(t, serverCfg.Certificates, 2)
+	addr := startSNITestServer(t, serverCfg)
+
+	cert := dialSNITestServer(t, addr, &tls.Config{ServerName: "localhost", InsecureSkipVerify: true})
+	assert.Equal(t, "localhost", cert.Subject.CommonName)
+
+	cert = dialSNITestServer(t, addr, &tls.Config{
+		ServerName:         "localhost",
+		InsecureSkipVerify: true,
+		MaxVersion:         tls.VersionTLS12,
+		CipherSuites:       []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	})
+	assert.Equal(t, "ecdsa-default", cert.Subject.CommonName)
+}
+
+func TestServerSNICertificates_ValidatesHostnames(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		hostnames []string
+		dnsNames  []string
+		wantErr   string
+	}{
+		{
+			name:      "hostname not in SANs",
+			hostnames: []string{"foo.example.com", "bar.example.com"},
+			dnsNames:  []string{"foo.example.com"},
+			wantErr:   "certificate is not valid for hostname bar.example.com: x509: certificate is valid for foo.example.com, not bar.example.com",
+		},
+		{
+			name:      "hostname covered by wildcard SAN",
+			hostnames: []string{"foo.example.com"},
+			dnsNames:  []string{"*.example.com"},
+		},
+		{
+			name:      "wildcard hostname requires wildcard SAN",
+			hostnames: []string{"*.example.com"},
+			dnsNames:  []string{"foo.example.com"},
+			wantErr:   "certificate with DNS names [foo.example.com] is not valid for wildcard hostname *.example.com",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tlsconfig.NewServerConfig(
+				tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+				tlsconfig.ServerSNICertificates(tlsconfig.SNICertificate{
+					Hostnames: tc.hostnames,
+					Provider:  newSNITestCertProvider(t, "test", false, tc.dnsNames...),
+				}),
+			)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func startSNITestServer(t *testing.T, cfg *tls.Config) string {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// dialSNITestServer performs a TLS handshake with the server at the provided address and returns the leaf certificate
+// presented by the server.
+func dialSNITestServer(t *testing.T, addr string, cfg *tls.Config) *x509.Certificate {
+	conn, err := tls.Dial("tcp", addr, cfg)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	return conn.ConnectionState().PeerCertificates[0]
+}
+
+// newSNITestCertProvider returns a provider for a new server certificate with the provided common name and DNS names
+// that is signed by the test CA.
+func newSNITestCertProvider(t *testing.T, commonName string, useECDSA bool, dnsNames ...string) tlsconfig.TLSCertProvider {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(time.Hour),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:    dnsNames,
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+	}, useECDSA)
+	return func() (tls.Certificate, error) {
+		return cert, nil
+	}
+}
+
+// newTestCert returns a new certificate created from the provided template that is signed by the test CA. The chain of
+// the returned certificate includes the test CA certificate.
+func newTestCert(t *testing.T, template *x509.Certificate, useECDSA bool) tls.Certificate {
+	caCert, err := tls.LoadX509KeyPair(caCertFile, "testdata/ca-key.pem")
+	require.NoError(t, err)
+	return newTestCertIssuedBy(t, template, useECDSA, caCert)
+}
+
+// newTestCertIssuedBy returns a new certificate created from the provided template that is signed by the provided
+// issuer. The chain of the returned certificate includes the issuer's chain.
+func newTestCertIssuedBy(t *testing.T, template *x509.Certificate, useECDSA bool, issuer tls.Certificate) tls.Certificate {
+	var key crypto.Signer
+	var err error
+	if useECDSA {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	} else {
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	require.NoError(t, err)
+
+	issuerLeaf, err := x509.ParseCertificate(issuer.Certificate[0])
+	require.NoError(t, err)
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	der, err := x509.CreateCertificate(rand.Reader, template, issuerLeaf, key.Public(), issuer.PrivateKey)
+	require.NoError(t, err)
+	return tls.Certificate{
+		Certificate: append([][]byte{der}, issuer.Certificate...),
+		PrivateKey:  key,
+	}
+}
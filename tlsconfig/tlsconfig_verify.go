@@ -0,0 +1,253 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const spkiPinPrefix = "sha256/"
+
+// SPKIPin returns the pin of the public key of the provided certificate: the base64-encoded SHA-256 hash of the
+// certificate's DER-encoded SubjectPublicKeyInfo, as used by HTTP Public Key Pinning (RFC 7469).
+func SPKIPin(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// ClientSPKIPins configures the client to require that the server's certificate chain contains a certificate whose
+// public key matches one of the provided pins, as returned by SPKIPin. Pins may optionally have a "sha256/" prefix.
+// Every certificate in the chains verified by crypto/tls is checked, so a pin may be for the server's key or the key
+// of an intermediate or root CA.
+//
+// If certificate verification is disabled (for example, using ClientInsecureSkipVerify), only the server's leaf
+// certificate is checked, so a client that trusts only the pinned keys can be configured by combining this parameter
+// with ClientInsecureSkipVerify.
+func ClientSPKIPins(pins ...string) ClientParam {
+	return clientParam(func(cfg *tls.Config) error {
+		if len(pins) == 0 {
+			return fmt.Errorf("no pins provided to ClientSPKIPins")
+		}
+		hashes := make([][]byte, len(pins))
+		for i, pin := range pins {
+			hash, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, spkiPinPrefix))
+			if err != nil || len(hash) != sha256.Size {
+				return fmt.Errorf("invalid SPKI pin %q: must be a base64-encoded SHA-256 hash", pin)
+			}
+			hashes[i] = hash
+		}
+		appendVerifyConnection(cfg, func(cs tls.ConnectionState) error {
+			var certs []*x509.Certificate
+			if len(cs.PeerCertificates) > 0 {
+				certs = append(certs, cs.PeerCertificates[0])
+			}
+			for _, chain := range cs.VerifiedChains {
+				certs = append(certs, chain...)
+			}
+			for _, cert := range certs {
+				hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				for _, pinned := range hashes {
+					if bytes.Equal(hash[:], pinned) {
+						return nil
+					}
+				}
+			}
+			return fmt.Errorf("tls: no certificate in the server's chain has a public key that matches a pinned key")
+		})
+		return nil
+	})
+}
+
+// ClientPinnedIntermediateFiles is identical to ClientPinnedIntermediates, but loads the intermediate certificates
+// from the provided PEM-encoded files.
+func ClientPinnedIntermediateFiles(files ...string) ClientParam {
+	return clientParam(func(cfg *tls.Config) error {
+		certs, err := readPEMCertificateFiles(files...)
+		if err != nil {
+			return err
+		}
+		return ClientPinnedIntermediates(certs...).configureClient(cfg)
+	})
+}
+
+// ClientPinnedIntermediates configures the client to require that the server's certificate is issued by one of the
+// provided intermediate CA certificates. The server's certificate is verified using the pinned intermediates as the
+// only trust anchors, in addition to the verification against the root CAs performed by crypto/tls, so a certificate
+// issued by a different intermediate of the same root CA is rejected.
+func ClientPinnedIntermediates(certs ...*x509.Certificate) ClientParam {
+	return clientParam(func(cfg *tls.Config) error {
+		if len(certs) == 0 {
+			return fmt.Errorf("no certificates provided to ClientPinnedIntermediates")
+		}
+		pinned := x509.NewCertPool()
+		for _, cert := range certs {
+			pinned.AddCert(cert)
+		}
+		appendVerifyConnection(cfg, func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("tls: server did not provide a certificate")
+			}
+			opts := x509.VerifyOptions{
+				Roots:         pinned,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+				return fmt.Errorf("tls: server certificate is not issued by a pinned intermediate: %v", err)
+			}
+			return nil
+		})
+		return nil
+	})
+}
+
+// ClientVerifyHostname configures the client to verify that the server's certificate is valid for the provided
+// hostname rather than the host that the client connects to, which is useful when connecting to a server using an
+// address that does not appear in its certificate. The server name sent to the server using SNI is not changed.
+//
+// The default verification performed by crypto/tls is disabled and the server's certificate chain is instead verified
+// in VerifyConnection against the RootCAs configured by the other parameters (or the system roots if none are
+// configured), or against the current CAs when used with NewRefreshableClientConfig. Returns an error if certificate
+// verification has already been disabled using InsecureSkipVerify.
+func ClientVerifyHostname(hostname string) ClientParam {
+	return verifyHostnameParam(hostname)
+}
+
+// verifyHostnameParam is the ClientParam returned by ClientVerifyHostname. It is a distinct type so that
+// NewRefreshableClientConfig can verify the hostname against its refreshable root CAs.
+type verifyHostnameParam string
+
+func (p verifyHostnameParam) configureClient(cfg *tls.Config) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if cfg.InsecureSkipVerify {
+		return fmt.Errorf("ClientVerifyHostname cannot be used when InsecureSkipVerify is set")
+	}
+	cfg.InsecureSkipVerify = true
+	rootCAs := cfg.RootCAs
+	cfg.VerifyConnection = verifyServerChain(func() (*x509.CertPool, error) {
+		return rootCAs, nil
+	}, func(tls.ConnectionState) (string, error) {
+		return string(p), nil
+	}, cfg.VerifyConnection)
+	return nil
+}
+
+func (p verifyHostnameParam) validate() error {
+	if p == "" {
+		return fmt.Errorf("hostname provided to ClientVerifyHostname was empty")
+	}
+	return nil
+}
+
+// ServerClientSANAllowlist configures the server to only accept client certificates with a subject alternative name
+// that matches one of the provided entries. DNS names, IP addresses, email addresses and URIs (such as SPIFFE IDs of
+// the form "spiffe://example.org/service") are matched. An entry that ends with "*" matches any name with the
+// preceding prefix, so "spiffe://example.org/*" matches every SPIFFE ID in the example.org trust domain.
+//
+// The allowlist is checked after the client's certificate has been verified, so this parameter should be combined
+// with ServerClientAuthType(tls.RequireAndVerifyClientCert). Connections from clients that do not provide a
+// certificate, or whose certificate was not verified by crypto/tls (for example, because the client authentication type
+// is tls.RequireAnyClientCert), are rejected.
+func ServerClientSANAllowlist(allowed ...string) ServerParam {
+	return serverParam(func(cfg *tls.Config) error {
+		if len(allowed) == 0 {
+			return fmt.Errorf("no entries provided to ServerClientSANAllowlist")
+		}
+		appendVerifyConnection(cfg, func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("tls: client did not provide a certificate")
+			}
+			if len(cs.VerifiedChains) == 0 {
+				return fmt.Errorf("tls: client certificate was not verified")
+			}
+			sans := subjectAlternativeNames(cs.PeerCertificates[0])
+			for _, san := range sans {
+				for _, entry := range allowed {
+					if prefix, ok := strings.CutSuffix(entry, "*"); (ok && strings.HasPrefix(san, prefix)) || san == entry {
+						return nil
+					}
+				}
+			}
+			return fmt.Errorf("tls: client certificate with subject alternative names %v is not authorized", sans)
+		})
+		return nil
+	})
+}
+
+func subjectAlternativeNames(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+// verifyServerChain returns a VerifyConnection function for configurations that disable the default verification
+// performed by crypto/tls. The function verifies the server's certificate chain against the pool returned by roots
+// (or the system roots if it is nil) and the name returned by serverName, and then calls next, if it is non-nil, with
+// the verified chains set on the connection state.
+func verifyServerChain(roots func() (*x509.CertPool, error), serverName func(tls.ConnectionState) (string, error), next func(tls.ConnectionState) error) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("tls: server did not provide a certificate")
+		}
+		certPool, err := roots()
+		if err != nil {
+			return err
+		}
+		name, err := serverName(cs)
+		if err != nil {
+			return err
+		}
+		opts := x509.VerifyOptions{
+			Roots:         certPool,
+			DNSName:       name,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		chains, err := cs.PeerCertificates[0].Verify(opts)
+		if err != nil {
+			return &tls.CertificateVerificationError{UnverifiedCertificates: cs.PeerCertificates, Err: err}
+		}
+		if next == nil {
+			return nil
+		}
+		cs.VerifiedChains = chains
+		return next(cs)
+	}
+}
+
+// appendVerifyConnection sets the VerifyConnection function of the provided configuration to a function that calls
+// the existing VerifyConnection function, if any, followed by verify.
+func appendVerifyConnection(cfg *tls.Config, verify func(tls.ConnectionState) error) {
+	prev := cfg.VerifyConnection
+	if prev == nil {
+		cfg.VerifyConnection = verify
+		return
+	}
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if err := prev(cs); err != nil {
+			return err
+		}
+		return verify(cs)
+	}
+}
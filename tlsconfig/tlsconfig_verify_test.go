@@ -0,0 +1,336 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/refreshable/v2"
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSPKIPins(t *testing.T) {
+	serverLeaf, err := tls.LoadX509KeyPair(serverCertFile, serverKeyFile)
+	require.NoError(t, err)
+	caCert := loadTestCert(t, caCertFile)
+	otherCert := loadTestCert(t, clientCertFile)
+
+	addr, _ := startVerifyTestServer(t, &tls.Config{Certificates: []tls.Certificate{serverLeaf}})
+
+	for _, tc := range []struct {
+		name    string
+		params  []tlsconfig.ClientParam
+		wantErr string
+	}{
+		{
+			name:   "pin of server key",
+			params: []tlsconfig.ClientParam{tlsconfig.ClientRootCAFiles(caCertFile), tlsconfig.ClientSPKIPins(tlsconfig.SPKIPin(serverLeaf.Leaf))},
+		},
+		{
+			name:   "pin of CA key with prefix",
+			params: []tlsconfig.ClientParam{tlsconfig.ClientRootCAFiles(caCertFile), tlsconfig.ClientSPKIPins("sha256/" + tlsconfig.SPKIPin(caCert))},
+		},
+		{
+			name:    "no matching pin",
+			params:  []tlsconfig.ClientParam{tlsconfig.ClientRootCAFiles(caCertFile), tlsconfig.ClientSPKIPins(tlsconfig.SPKIPin(otherCert))},
+			wantErr: "tls: no certificate in the server's chain has a public key that matches a pinned key",
+		},
+		{
+			name:   "pin without certificate verification",
+			params: []tlsconfig.ClientParam{tlsconfig.ClientInsecureSkipVerify(), tlsconfig.ClientSPKIPins(tlsconfig.SPKIPin(serverLeaf.Leaf))},
+		},
+		{
+			name:    "CA pin without certificate verification",
+			params:  []tlsconfig.ClientParam{tlsconfig.ClientInsecureSkipVerify(), tlsconfig.ClientSPKIPins(tlsconfig.SPKIPin(caCert))},
+			wantErr: "tls: no certificate in the server's chain has a public key that matches a pinned key",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clientCfg, err := tlsconfig.NewClientConfig(tc.params...)
+			require.NoError(t, err)
+			clientCfg.ServerName = "localhost"
+			err = dialVerifyTestServer(addr, clientCfg)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+
+	_, err = tlsconfig.NewClientConfig(tlsconfig.ClientSPKIPins("not-a-pin"))
+	assert.EqualError(t, err, `invalid SPKI pin "not-a-pin": must be a base64-encoded SHA-256 hash`)
+}
+
+func TestClientPinnedIntermediates(t *testing.T) {
+	pinnedCA := newTestIntermediateCA(t, "pinned")
+	otherCA := newTestIntermediateCA(t, "other")
+	pinnedCACert, err := x509.ParseCertificate(pinnedCA.Certificate[0])
+	require.NoError(t, err)
+
+	clientCfg, err := tlsconfig.NewClientConfig(
+		tlsconfig.ClientRootCAFiles(caCertFile),
+		tlsconfig.ClientPinnedIntermediates(pinnedCACert),
+	)
+	require.NoError(t, err)
+	clientCfg.ServerName = "localhost"
+
+	addr, _ := startVerifyTestServer(t, &tls.Config{Certificates: []tls.Certificate{newTestServerCertIssuedBy(t, pinnedCA, "localhost")}})
+	assert.NoError(t, dialVerifyTestServer(addr, clientCfg))
+
+	addr, _ = startVerifyTestServer(t, &tls.Config{Certificates: []tls.Certificate{newTestServerCertIssuedBy(t, otherCA, "localhost")}})
+	err = dialVerifyTestServer(addr, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tls: server certificate is not issued by a pinned intermediate: x509: certificate signed by unknown authority")
+}
+
+func TestClientVerifyHostname(t *testing.T) {
+	ca := newTestIntermediateCA(t, "intermediate")
+	addr, _ := startVerifyTestServer(t, &tls.Config{Certificates: []tls.Certificate{newTestServerCertIssuedBy(t, ca, "service.internal")}})
+
+	clientCfg, err := tlsconfig.NewClientConfig(tlsconfig.ClientRootCAFiles(caCertFile))
+	require.NoError(t, err)
+	clientCfg.ServerName = "localhost"
+	err = dialVerifyTestServer(addr, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate is valid for service.internal, not localhost")
+
+	clientCfg, err = tlsconfig.NewClientConfig(tlsconfig.ClientRootCAFiles(caCertFile), tlsconfig.ClientVerifyHostname("service.internal"))
+	require.NoError(t, err)
+	clientCfg.ServerName = "localhost"
+	assert.NoError(t, dialVerifyTestServer(addr, clientCfg))
+
+	clientCfg, err = tlsconfig.NewClientConfig(tlsconfig.ClientRootCAFiles(caCertFile), tlsconfig.ClientVerifyHostname("other.internal"))
+	require.NoError(t, err)
+	clientCfg.ServerName = "localhost"
+	err = dialVerifyTestServer(addr, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate is valid for service.internal, not other.internal")
+
+	// the certificate chain is still verified
+	clientCfg, err = tlsconfig.NewClientConfig(tlsconfig.ClientRootCAFiles(clientCertFile), tlsconfig.ClientVerifyHostname("service.internal"))
+	require.NoError(t, err)
+	err = dialVerifyTestServer(addr, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+
+	// the root CAs are used regardless of the order of the parameters
+	clientCfg, err = tlsconfig.NewClientConfig(tlsconfig.ClientVerifyHostname("service.internal"), tlsconfig.ClientRootCAFiles(caCertFile))
+	require.NoError(t, err)
+	assert.NoError(t, dialVerifyTestServer(addr, clientCfg))
+
+	_, err = tlsconfig.NewClientConfig(tlsconfig.ClientInsecureSkipVerify(), tlsconfig.ClientVerifyHostname("service.internal"))
+	assert.EqualError(t, err, "ClientVerifyHostname cannot be used when InsecureSkipVerify is set")
+}
+
+func TestClientVerifyHostname_RefreshableClientConfig(t *testing.T) {
+	ca := newTestIntermediateCA(t, "intermediate")
+	addr, _ := startVerifyTestServer(t, &tls.Config{Certificates: []tls.Certificate{newTestServerCertIssuedBy(t, ca, "service.internal")}})
+
+	// the server's certificate is issued by a private CA, so it is only trusted using the refreshable CAs
+	caPEM := refreshable.New(readFile(t, caCertFile))
+	clientCfg, err := tlsconfig.NewRefreshableClientConfig(caPEM, tlsconfig.ClientVerifyHostname("service.internal"))
+	require.NoError(t, err)
+	assert.NoError(t, dialVerifyTestServer(addr, clientCfg))
+
+	caPEM.Update(readFile(t, clientCertFile))
+	err = dialVerifyTestServer(addr, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+
+	caPEM.Update(readFile(t, caCertFile))
+	assert.NoError(t, dialVerifyTestServer(addr, clientCfg))
+
+	clientCfg, err = tlsconfig.NewRefreshableClientConfig(caPEM, tlsconfig.ClientVerifyHostname("other.internal"))
+	require.NoError(t, err)
+	err = dialVerifyTestServer(addr, clientCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate is valid for service.internal, not other.internal")
+}
+
+func TestServerClientSANAllowlist(t *testing.T) {
+	serverCfg, err := tlsconfig.NewServerConfig(
+		tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+		tlsconfig.ServerClientAuthType(tls.RequireAndVerifyClientCert),
+		tlsconfig.ServerClientCAFiles(caCertFile),
+		tlsconfig.ServerClientSANAllowlist("spiffe://example.org/allowed/*", "client.example.com"),
+	)
+	require.NoError(t, err)
+	addr, serverErrs := startVerifyTestServer(t, serverCfg)
+
+	for _, tc := range []struct {
+		name     string
+		dnsNames []string
+		uris     []string
+		wantErr  string
+	}{
+		{
+			name: "SPIFFE ID matches prefix",
+			uris: []string{"spiffe://example.org/allowed/service"},
+		},
+		{
+			name:     "DNS name matches",
+			dnsNames: []string{"client.example.com"},
+		},
+		{
+			name:    "SPIFFE ID does not match",
+			uris:    []string{"spiffe://example.org/denied/service"},
+			wantErr: "tls: client certificate with subject alternative names [spiffe://example.org/denied/service] is not authorized",
+		},
+		{
+			name:    "no SANs",
+			wantErr: "tls: client certificate with subject alternative names [] is not authorized",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			template := &x509.Certificate{
+				Subject:     pkix.Name{CommonName: "client"},
+				NotBefore:   time.Now().Add(-time.Hour),
+				NotAfter:    time.Now().Add(time.Hour),
+				KeyUsage:    x509.KeyUsageDigitalSignature,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				DNSNames:    tc.dnsNames,
+			}
+			for _, uri := range tc.uris {
+				parsed, err := url.Parse(uri)
+				require.NoError(t, err)
+				template.URIs = append(template.URIs, parsed)
+			}
+			clientCert := newTestCert(t, template, true)
+			clientCfg, err := tlsconfig.NewClientConfig(
+				tlsconfig.ClientKeyPair(func() (tls.Certificate, error) {
+					return clientCert, nil
+				}),
+				tlsconfig.ClientRootCAFiles(caCertFile),
+			)
+			require.NoError(t, err)
+			clientCfg.ServerName = "localhost"
+
+			_ = dialVerifyTestServer(addr, clientCfg)
+			err = <-serverErrs
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerClientSANAllowlist_UnverifiedCertificate(t *testing.T) {
+	serverCfg, err := tlsconfig.NewServerConfig(
+		tlsconfig.TLSCertFromFiles(serverCertFile, serverKeyFile),
+		tlsconfig.ServerClientAuthType(tls.RequireAnyClientCert),
+		tlsconfig.ServerClientSANAllowlist("spiffe://example.org/allowed/*"),
+	)
+	require.NoError(t, err)
+	addr, serverErrs := startVerifyTestServer(t, serverCfg)
+
+	// a self-signed certificate with an allowed SAN is not authorized
+	allowedURI, err := url.Parse("spiffe://example.org/allowed/service")
+	require.NoError(t, err)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{allowedURI},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	clientCert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	clientCfg, err := tlsconfig.NewClientConfig(
+		tlsconfig.ClientKeyPair(func() (tls.Certificate, error) {
+			return clientCert, nil
+		}),
+		tlsconfig.ClientRootCAFiles(caCertFile),
+	)
+	require.NoError(t, err)
+	clientCfg.ServerName = "localhost"
+
+	_ = dialVerifyTestServer(addr, clientCfg)
+	assert.EqualError(t, <-serverErrs, "tls: client certificate was not verified")
+}
+
+// startVerifyTestServer starts a TLS server that closes each connection after completing the handshake and returns
+// its address and a channel that receives the result of each handshake.
+func startVerifyTestServer(t *testing.T, cfg *tls.Config) (string, <-chan error) {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	errs := make(chan error, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			errs <- conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String(), errs
+}
+
+// dialVerifyTestServer performs a TLS handshake with the server at the provided address and waits for the server to
+// close the connection.
+func dialVerifyTestServer(addr string, cfg *tls.Config) error {
+	conn, err := tls.Dial("tcp", addr, cfg)
+	if err != nil {
+		return err
+	}
+	_, _ = conn.Read(make([]byte, 1))
+	return conn.Close()
+}
+
+func newTestIntermediateCA(t *testing.T, commonName string) tls.Certificate {
+	return newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, true)
+}
+
+func newTestServerCertIssuedBy(t *testing.T, issuer tls.Certificate, dnsNames ...string) tls.Certificate {
+	return newTestCertIssuedBy(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "server"},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(time.Hour),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:    dnsNames,
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+	}, true, issuer)
+}
+
+func loadTestCert(t *testing.T, certFile string) *x509.Certificate {
+	block, _ := pem.Decode(readFile(t, certFile))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
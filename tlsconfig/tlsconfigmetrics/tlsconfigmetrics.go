@@ -0,0 +1,35 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tlsconfigmetrics publishes the certificate expiry reported by a tlsconfig.ExpiryMonitor as metrics. It is a
+// separate package so that the tlsconfig package does not depend on the metrics package.
+package tlsconfigmetrics
+
+import (
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/tlsconfig"
+)
+
+const (
+	// ExpiryDaysGaugeName is the name of the gauge whose value is the number of days until the earliest certificate of
+	// a monitored source expires. The value is negative once the certificate has expired.
+	ExpiryDaysGaugeName = "tls.certificate.expiry.days"
+	// CertificateTagKey is the key of the tag whose value is the name of the monitored source.
+	CertificateTagKey = "certificate"
+)
+
+// ExpiryDaysGauge returns an ExpiryHandler that updates the ExpiryDaysGaugeName gauge on the provided registry, tagged
+// with the name of the source using the key CertificateTagKey, for example:
+//
+//	monitor := tlsconfig.NewExpiryMonitor(tlsconfigmetrics.ExpiryDaysGauge(metrics.FromContext(ctx)))
+//	monitor.AddTLSCert("server", certProvider)
+//	go monitor.Run(ctx, time.Hour, nil)
+func ExpiryDaysGauge(registry metrics.Registry) tlsconfig.ExpiryHandler {
+	return func(name string, notAfter time.Time) {
+		tag := metrics.NewTagWithFallbackValue(CertificateTagKey, name, "unknown")
+		registry.GaugeFloat64(ExpiryDaysGaugeName, tag).Update(time.Until(notAfter).Hours() / 24)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfigmetrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/palantir/pkg/tlsconfig/tlsconfigmetrics"
+	"github.com/palantir/pkg/tlsconfig/tlsconfigtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryDaysGauge(t *testing.T) {
+	ca := tlsconfigtest.NewCA(t)
+	cert := ca.NewServerCert(t, tlsconfigtest.Validity(time.Now().Add(-time.Hour), time.Now().Add(10*24*time.Hour)))
+
+	registry := metrics.NewRootMetricsRegistry()
+	monitor := tlsconfig.NewExpiryMonitor(tlsconfigmetrics.ExpiryDaysGauge(registry))
+	monitor.AddTLSCert("server", cert.TLSCertProvider())
+	require.NoError(t, monitor.Update())
+
+	days := registry.GaugeFloat64(tlsconfigmetrics.ExpiryDaysGaugeName, metrics.MustNewTag("certificate", "server")).Value()
+	assert.InDelta(t, 10, days, 0.01)
+}
@@ -0,0 +1,380 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tlsconfigtest generates certificate authorities and certificates in memory for use in tests, so that tests of
+// TLS and mutual TLS connections do not need checked-in PEM files. Certificates can be used directly as
+// tlsconfig.TLSCertProvider and tlsconfig.CertPoolProvider values or written to files.
+package tlsconfigtest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/tlsconfig"
+)
+
+// KeyType is the type of the key pair generated for a certificate.
+type KeyType int
+
+const (
+	ECDSAP256 KeyType = iota
+	ECDSAP384
+	RSA2048
+	RSA4096
+	Ed25519
+)
+
+// CertOption configures a generated certificate.
+type CertOption func(*certOptions)
+
+type certOptions struct {
+	template *x509.Certificate
+	keyType  KeyType
+}
+
+// CommonName sets the common name of the certificate's subject.
+func CommonName(commonName string) CertOption {
+	return func(o *certOptions) {
+		o.template.Subject.CommonName = commonName
+	}
+}
+
+// Organization sets the organization of the certificate's subject.
+func Organization(organization string) CertOption {
+	return func(o *certOptions) {
+		o.template.Subject.Organization = []string{organization}
+	}
+}
+
+// DNSNames sets the DNS subject alternative names of the certificate, replacing any defaults.
+func DNSNames(dnsNames ...string) CertOption {
+	return func(o *certOptions) {
+		o.template.DNSNames = dnsNames
+	}
+}
+
+// IPAddresses sets the IP address subject alternative names of the certificate, replacing any defaults.
+func IPAddresses(ips ...net.IP) CertOption {
+	return func(o *certOptions) {
+		o.template.IPAddresses = ips
+	}
+}
+
+// URIs sets the URI subject alternative names of the certificate, such as SPIFFE IDs. Panics if a URI cannot be
+// parsed.
+func URIs(uris ...string) CertOption {
+	return func(o *certOptions) {
+		o.template.URIs = nil
+		for _, uri := range uris {
+			parsed, err := url.Parse(uri)
+			if err != nil {
+				panic(err)
+			}
+			o.template.URIs = append(o.template.URIs, parsed)
+		}
+	}
+}
+
+// EmailAddresses sets the email address subject alternative names of the certificate.
+func EmailAddresses(emails ...string) CertOption {
+	return func(o *certOptions) {
+		o.template.EmailAddresses = emails
+	}
+}
+
+// Validity sets the period during which the certificate is valid. The default is from one hour before the certificate
+// is generated until 24 hours after it is generated. A period in the past can be used to generate an expired
+// certificate.
+func Validity(notBefore, notAfter time.Time) CertOption {
+	return func(o *certOptions) {
+		o.template.NotBefore = notBefore
+		o.template.NotAfter = notAfter
+	}
+}
+
+// ValidFor sets the certificate to be valid from one hour before it is generated until the provided duration after it
+// is generated.
+func ValidFor(d time.Duration) CertOption {
+	now := time.Now()
+	return Validity(now.Add(-time.Hour), now.Add(d))
+}
+
+// WithKeyType sets the type of the key pair generated for the certificate. The default is ECDSAP256.
+func WithKeyType(keyType KeyType) CertOption {
+	return func(o *certOptions) {
+		o.keyType = keyType
+	}
+}
+
+// ExtKeyUsages sets the extended key usages of the certificate, replacing any defaults.
+func ExtKeyUsages(usages ...x509.ExtKeyUsage) CertOption {
+	return func(o *certOptions) {
+		o.template.ExtKeyUsage = usages
+	}
+}
+
+// CA is a generated certificate authority that can issue certificates.
+type CA struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+
+	// chain is the DER-encoded certificates of this CA and its issuers, excluding the root CA.
+	chain [][]byte
+	root  *x509.Certificate
+}
+
+// Cert is a generated certificate and its private key.
+type Cert struct {
+	Leaf *x509.Certificate
+	Key  crypto.Signer
+	// Chain is the DER-encoded certificate chain presented by the certificate's owner: the leaf certificate followed by
+	// any intermediate CA certificates. It does not include the root CA certificate.
+	Chain [][]byte
+}
+
+// NewCA returns a new self-signed root CA. Calls t.Fatal if the CA cannot be generated.
+func NewCA(t testing.TB, opts ...CertOption) *CA {
+	t.Helper()
+	template := caTemplate("Test Root CA")
+	key, der := generate(t, template, nil, nil, opts)
+	cert := mustParseCertificate(t, der)
+	return &CA{
+		Cert: cert,
+		Key:  key,
+		root: cert,
+	}
+}
+
+// NewIntermediate returns a new intermediate CA issued by this CA. Calls t.Fatal if the CA cannot be generated.
+func (ca *CA) NewIntermediate(t testing.TB, opts ...CertOption) *CA {
+	t.Helper()
+	template := caTemplate("Test Intermediate CA")
+	key, der := generate(t, template, ca.Cert, ca.Key, opts)
+	return &CA{
+		Cert:  mustParseCertificate(t, der),
+		Key:   key,
+		chain: append([][]byte{der}, ca.chain...),
+		root:  ca.root,
+	}
+}
+
+// NewServerCert returns a new server certificate issued by this CA. By default, the certificate's common name and DNS
+// name are "localhost" and its IP addresses are 127.0.0.1 and ::1. Calls t.Fatal if the certificate cannot be
+// generated.
+func (ca *CA) NewServerCert(t testing.TB, opts ...CertOption) *Cert {
+	t.Helper()
+	template := leafTemplate("localhost", x509.ExtKeyUsageServerAuth)
+	template.DNSNames = []string{"localhost"}
+	template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	return ca.newCert(t, template, opts)
+}
+
+// NewClientCert returns a new client certificate issued by this CA. By default, the certificate's common name is
+// "client" and it does not have any subject alternative names. Calls t.Fatal if the certificate cannot be generated.
+func (ca *CA) NewClientCert(t testing.TB, opts ...CertOption) *Cert {
+	t.Helper()
+	return ca.newCert(t, leafTemplate("client", x509.ExtKeyUsageClientAuth), opts)
+}
+
+func (ca *CA) newCert(t testing.TB, template *x509.Certificate, opts []CertOption) *Cert {
+	t.Helper()
+	key, der := generate(t, template, ca.Cert, ca.Key, opts)
+	return &Cert{
+		Leaf:  mustParseCertificate(t, der),
+		Key:   key,
+		Chain: append([][]byte{der}, ca.chain...),
+	}
+}
+
+// CertPEM returns the PEM-encoded certificate of the root CA of this CA. This is the certificate that should be
+// trusted by the peers of the owners of certificates issued by this CA.
+func (ca *CA) CertPEM() []byte {
+	return encodeCertificates(ca.root.Raw)
+}
+
+// CertPool returns a new pool that contains the root CA of this CA.
+func (ca *CA) CertPool() *x509.CertPool {
+	certPool := x509.NewCertPool()
+	certPool.AddCert(ca.root)
+	return certPool
+}
+
+// CertPoolProvider returns a provider that returns a new pool that contains the root CA of this CA.
+func (ca *CA) CertPoolProvider() tlsconfig.CertPoolProvider {
+	return func() (*x509.CertPool, error) {
+		return ca.CertPool(), nil
+	}
+}
+
+// WriteCertFile writes the PEM-encoded root CA certificate to a file named "ca.pem" in the provided directory and
+// returns the path of the file. If dir is empty, a directory created using t.TempDir is used.
+func (ca *CA) WriteCertFile(t testing.TB, dir string) string {
+	t.Helper()
+	if dir == "" {
+		dir = t.TempDir()
+	}
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, ca.CertPEM(), 0644)
+	return caFile
+}
+
+// TLSCertificate returns the certificate as a tls.Certificate.
+func (c *Cert) TLSCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: c.Chain,
+		PrivateKey:  c.Key,
+		Leaf:        c.Leaf,
+	}
+}
+
+// TLSCertProvider returns a provider that returns the certificate as a tls.Certificate.
+func (c *Cert) TLSCertProvider() tlsconfig.TLSCertProvider {
+	return func() (tls.Certificate, error) {
+		return c.TLSCertificate(), nil
+	}
+}
+
+// CertPEM returns the PEM-encoded certificate chain.
+func (c *Cert) CertPEM() []byte {
+	return encodeCertificates(c.Chain...)
+}
+
+// KeyPEM returns the PEM-encoded private key in PKCS #8 form.
+func (c *Cert) KeyPEM() []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(c.Key)
+	if err != nil {
+		// all of the key types generated by this package can be marshalled
+		panic(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// PEMKeyPair returns the PEM-encoded certificate chain and private key.
+func (c *Cert) PEMKeyPair() tlsconfig.PEMKeyPair {
+	return tlsconfig.PEMKeyPair{
+		Cert: c.CertPEM(),
+		Key:  c.KeyPEM(),
+	}
+}
+
+// WriteFiles writes the PEM-encoded certificate chain and private key to files named "cert.pem" and "key.pem" in the
+// provided directory and returns the paths of the files. If dir is empty, a directory created using t.TempDir is used.
+func (c *Cert) WriteFiles(t testing.TB, dir string) (certFile, keyFile string) {
+	t.Helper()
+	if dir == "" {
+		dir = t.TempDir()
+	}
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	writeFile(t, certFile, c.CertPEM(), 0644)
+	writeFile(t, keyFile, c.KeyPEM(), 0600)
+	return certFile, keyFile
+}
+
+func caTemplate(commonName string) *x509.Certificate {
+	now := time.Now()
+	return &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+}
+
+func leafTemplate(commonName string, usage x509.ExtKeyUsage) *x509.Certificate {
+	now := time.Now()
+	return &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(24 * time.Hour),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{usage},
+	}
+}
+
+// generate creates a key pair and a certificate from the template with the provided options applied. The certificate
+// is signed by the provided issuer, or is self-signed if issuer is nil.
+func generate(t testing.TB, template, issuer *x509.Certificate, issuerKey crypto.Signer, opts []CertOption) (crypto.Signer, []byte) {
+	t.Helper()
+	o := &certOptions{template: template}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	key, err := generateKey(o.keyType)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		t.Fatalf("failed to generate serial number: %v", err)
+	}
+	template.SerialNumber = serial
+	if issuer == nil {
+		issuer, issuerKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), issuerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	return key, der
+}
+
+func generateKey(keyType KeyType) (crypto.Signer, error) {
+	switch keyType {
+	case ECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case RSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case RSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case Ed25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+}
+
+func mustParseCertificate(t testing.TB, der []byte) *x509.Certificate {
+	t.Helper()
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse generated certificate: %v", err)
+	}
+	return cert
+}
+
+func encodeCertificates(ders ...[]byte) []byte {
+	var out []byte
+	for _, der := range ders {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	return out
+}
+
+func writeFile(t testing.TB, path string, data []byte, perm os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, data, perm); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfigtest_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/tlsconfig"
+	"github.com/palantir/pkg/tlsconfig/tlsconfigtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutualTLS(t *testing.T) {
+	root := tlsconfigtest.NewCA(t)
+	intermediate := root.NewIntermediate(t)
+	serverCert := intermediate.NewServerCert(t)
+	clientCert := root.NewClientCert(t, tlsconfigtest.URIs("spiffe://example.org/client"))
+
+	serverCfg, err := tlsconfig.NewServerConfig(
+		serverCert.TLSCertProvider(),
+		tlsconfig.ServerClientAuthType(tls.RequireAndVerifyClientCert),
+		tlsconfig.ServerClientCAs(root.CertPoolProvider()),
+		tlsconfig.ServerClientSANAllowlist("spiffe://example.org/client"),
+	)
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, req.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = serverCfg
+	server.StartTLS()
+	defer server.Close()
+
+	clientCfg, err := tlsconfig.NewClientConfig(
+		tlsconfig.ClientKeyPair(clientCert.TLSCertProvider()),
+		tlsconfig.ClientRootCAs(root.CertPoolProvider()),
+	)
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientCfg}}
+	resp, err := client.Get(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "client", string(body))
+}
+
+func TestWriteFiles(t *testing.T) {
+	ca := tlsconfigtest.NewCA(t)
+	cert := ca.NewServerCert(t, tlsconfigtest.WithKeyType(tlsconfigtest.RSA2048))
+
+	certFile, keyFile := cert.WriteFiles(t, "")
+	caFile := ca.WriteCertFile(t, "")
+
+	serverCfg, err := tlsconfig.NewServerConfig(tlsconfig.TLSCertFromFiles(certFile, keyFile))
+	require.NoError(t, err)
+	clientCfg, err := tlsconfig.NewClientConfig(tlsconfig.ClientRootCAFiles(caFile))
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	server.TLS = serverCfg
+	server.StartTLS()
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientCfg}}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+}
+
+func TestCertOptions(t *testing.T) {
+	notAfter := time.Now().Add(-time.Hour).Truncate(time.Second)
+	ca := tlsconfigtest.NewCA(t, tlsconfigtest.CommonName("My CA"))
+	cert := ca.NewServerCert(t,
+		tlsconfigtest.CommonName("my-service"),
+		tlsconfigtest.Organization("My Org"),
+		tlsconfigtest.DNSNames("my-service.example.com"),
+		tlsconfigtest.IPAddresses(),
+		tlsconfigtest.Validity(notAfter.Add(-time.Hour), notAfter),
+		tlsconfigtest.WithKeyType(tlsconfigtest.Ed25519),
+	)
+
+	infos, err := tlsconfig.InspectPEM(cert.CertPEM())
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "CN=my-service,O=My Org", infos[0].Subject)
+	assert.Equal(t, "CN=My CA", infos[0].Issuer)
+	assert.Equal(t, []string{"my-service.example.com"}, infos[0].DNSNames)
+	assert.Empty(t, infos[0].IPAddresses)
+	assert.Equal(t, "Ed25519", infos[0].KeyType)
+	assert.True(t, notAfter.Equal(infos[0].NotAfter))
+
+	_, err = cert.Leaf.Verify(x509.VerifyOptions{Roots: ca.CertPool(), DNSName: "my-service.example.com"})
+	assert.ErrorContains(t, err, "certificate has expired or is not yet valid")
+}
+
+func TestPEMKeyPair(t *testing.T) {
+	for _, keyType := range []tlsconfigtest.KeyType{
+		tlsconfigtest.ECDSAP256,
+		tlsconfigtest.ECDSAP384,
+		tlsconfigtest.RSA2048,
+		tlsconfigtest.Ed25519,
+	} {
+		cert := tlsconfigtest.NewCA(t).NewIntermediate(t).NewClientCert(t, tlsconfigtest.WithKeyType(keyType))
+		keyPair := cert.PEMKeyPair()
+		tlsCert, err := tls.X509KeyPair(keyPair.Cert, keyPair.Key)
+		require.NoError(t, err)
+		assert.Len(t, tlsCert.Certificate, 2)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"reflect"
+)
+
+// Redactable is implemented by values that redact their contents when they are marshalled, such as
+// bearertoken.Secret.
+type Redactable interface {
+	// Unredacted returns a copy of the value that marshals its contents instead of redacting them. The returned value
+	// must have the same type as the receiver.
+	Unredacted() interface{}
+}
+
+// RevealRedacted returns a copy of in in which every Redactable value has been replaced by its Unredacted value. The
+// result will contain secrets and must not be logged or otherwise exposed.
+//
+// Redactable values are found by walking maps, slices, arrays, pointers and structs. Values stored in structs that
+// have unexported fields are not revealed.
+func RevealRedacted(in interface{}) interface{} {
+	return Rules{revealRedactable}.Apply(in)
+}
+
+func revealRedactable(r Redactable) interface{} {
+	rv := reflect.ValueOf(r)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return r
+	}
+	unredacted := r.Unredacted()
+	if rv.Kind() == reflect.Ptr && reflect.TypeOf(unredacted) == rv.Type().Elem() {
+		// pointers to values with an Unredacted method implement Redactable, so return a pointer of the same type
+		ptr := reflect.New(rv.Type().Elem())
+		ptr.Elem().Set(reflect.ValueOf(unredacted))
+		return ptr.Interface()
+	}
+	return unredacted
+}
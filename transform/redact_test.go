@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSecret struct {
+	value    string
+	revealed bool
+}
+
+func (s testSecret) Unredacted() interface{} {
+	return testSecret{value: s.value, revealed: true}
+}
+
+func TestRevealRedacted(t *testing.T) {
+	type config struct {
+		Password    testSecret
+		Token       *testSecret
+		NilToken    *testSecret
+		Credentials map[string]interface{}
+		Keys        []testSecret
+		Redactable  Redactable
+	}
+	in := config{
+		Password:    testSecret{value: "password"},
+		Token:       &testSecret{value: "token"},
+		Credentials: map[string]interface{}{"key": testSecret{value: "key"}},
+		Keys:        []testSecret{{value: "key-1"}},
+		Redactable:  testSecret{value: "redactable"},
+	}
+
+	out := RevealRedacted(in)
+	assert.Equal(t, config{
+		Password:    testSecret{value: "password", revealed: true},
+		Token:       &testSecret{value: "token", revealed: true},
+		Credentials: map[string]interface{}{"key": testSecret{value: "key", revealed: true}},
+		Keys:        []testSecret{{value: "key-1", revealed: true}},
+		Redactable:  testSecret{value: "redactable", revealed: true},
+	}, out)
+	assert.False(t, in.Password.revealed || in.Token.revealed, "the input is not modified")
+
+	assert.Equal(t, testSecret{value: "secret", revealed: true}, RevealRedacted(testSecret{value: "secret"}))
+	assert.Nil(t, RevealRedacted(nil))
+}
@@ -115,6 +115,10 @@ func (rules Rules) applyStruct(in reflect.Value) reflect.Value {
 
 func applyRule(rule interface{}, target reflect.Type, in reflect.Value) reflect.Value {
 	result := reflect.ValueOf(rule).Call([]reflect.Value{in})[0]
+	if result.Kind() == reflect.Interface && !result.IsNil() {
+		// use the dynamic value returned by the rule so that it can replace a value of a concrete type
+		result = result.Elem()
+	}
 	if !result.IsValid() && !canBeNil(target) {
 		return in
 	}
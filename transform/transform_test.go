@@ -92,6 +92,18 @@ func TestTransform(t *testing.T) {
 				Interface: json.Number("4"),
 			},
 		},
+		{
+			name:  "dynamic value of interface result assignable to field type",
+			rules: Rules{negateBigFloat},
+			input: &testStruct{
+				BigFloat:  big.NewFloat(1),
+				Interface: big.NewFloat(2),
+			},
+			expected: &testStruct{
+				BigFloat:  big.NewFloat(-1),
+				Interface: big.NewFloat(-2),
+			},
+		},
 		{
 			name:  "array",
 			rules: Rules{bigFloatToJSONNumber},
@@ -123,3 +135,7 @@ func bigFloatToJSONNumber(bf *big.Float) json.Number {
 func jsonNumberToNil(jn json.Number) interface{} {
 	return nil
 }
+
+func negateBigFloat(bf *big.Float) interface{} {
+	return new(big.Float).Neg(bf)
+}